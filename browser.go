@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"github.com/spf13/pflag"
+)
+
+// browserReadyTimeout bounds how long to wait for the server before giving up on opening the browser
+const browserReadyTimeout = 10 * time.Second
+
+// registerOpenBrowserFlag adds the --open-browser flag and its --open alias to fs
+func registerOpenBrowserFlag(fs *pflag.FlagSet) *bool {
+	open := fs.Bool("open-browser", false, "Open the documentation page in the default browser once the server is listening")
+	fs.BoolVar(open, "open", false, "Alias for --open-browser")
+	return open
+}
+
+// docsURL returns the documentation page of the configured server
+func docsURL(cfg *config.Config) string {
+	scheme := "http"
+	if cfg.TLS.Enabled {
+		scheme = "https"
+	}
+	return scheme + "://" + browserAddress(cfg) + constants.PathDocumentation
+}
+
+// browserAddress returns the host:port a local browser can reach the server on
+func browserAddress(cfg *config.Config) string {
+	host := cfg.Server.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, cfg.Server.Port)
+}
+
+// openWhenListening waits until address accepts connections and then calls
+// open with url. Failures are logged and never stop the server.
+func openWhenListening(ctx context.Context, address, url string, open func(string) error) {
+	ctx, cancel := context.WithTimeout(ctx, browserReadyTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{Timeout: time.Second}
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			_ = conn.Close()
+			break
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("Not opening browser: server at %s did not become reachable", address)
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if err := open(url); err != nil {
+		log.Printf("Failed to open browser: %v", err)
+	}
+}
+
+// openBrowser opens url in the default browser of the current platform. It is
+// a no-op without a graphical session, e.g. over SSH or in a container.
+func openBrowser(url string) error {
+	if headless(runtime.GOOS, os.Getenv) {
+		log.Printf("No display available, open %s to see the documentation", url)
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	// #nosec G204 - the launcher is fixed per platform and url is built from the server config
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the launcher without waiting on it
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// headless reports whether the platform has no display to open a browser on.
+// macOS and Windows are assumed to always have one.
+func headless(goos string, getenv func(string) string) bool {
+	if goos == "darwin" || goos == "windows" {
+		return false
+	}
+	return getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == ""
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+	"github.com/leslieo2/go-spec-mock/internal/server"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+// genCommand is the subcommand that prints a generated example without starting a server
+const genCommand = "gen"
+
+// runGen prints the example the server would return for an operation, generated
+// with the same parser and generator settings
+func runGen(args []string, stdout io.Writer) error {
+	fs := pflag.NewFlagSet(genCommand, pflag.ContinueOnError)
+	configFile := fs.String("config", "", "Path to configuration file (YAML or JSON)")
+	specFile := fs.String("spec", "", "Path to OpenAPI specification file")
+	operation := fs.String("operation", "", `Operation to generate, by operationId or as "METHOD /path"`)
+	status := fs.String("status", "200", "Response status code to generate")
+	example := fs.String("example", "", "Named example to use instead of the default one")
+	seed := fs.Int64("seed", 0, "Seed for reproducible data; 0 generates different data each run")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *operation == "" {
+		return errors.New("gen: --operation is required")
+	}
+
+	cfg, err := config.LoadConfig(*configFile, nil)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if *specFile != "" {
+		cfg.SpecFile = *specFile
+	}
+	if cfg.SpecFile == "" {
+		return errors.New("gen: --spec is required")
+	}
+
+	p, err := server.LoadParser(cfg, zap.NewNop())
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+	genConfig := p.GeneratorConfig()
+	genConfig.Seed = *seed
+	p.SetGeneratorConfig(genConfig)
+
+	route, ok := p.FindOperation(*operation)
+	if !ok {
+		return fmt.Errorf("gen: operation not found: %s", *operation)
+	}
+	body, err := p.GenerateExampleResponse(route.Operation, *status, *example)
+	if err != nil {
+		return fmt.Errorf("gen: %s %s: %w", route.Method, route.Path, err)
+	}
+	if cfg.Response.PreserveOrder {
+		if _, mediaType, err := parser.ResponseContent(route.Operation, *status); err == nil && mediaType != nil && mediaType.Schema != nil {
+			body = p.OrderProperties(body, mediaType.Schema.Value)
+		}
+	}
+
+	data, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize example: %w", err)
+	}
+	_, err = fmt.Fprintf(stdout, "%s\n", data)
+	return err
+}
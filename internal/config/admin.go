@@ -0,0 +1,15 @@
+package config
+
+// AdminConfig contains configuration for the runtime admin endpoints
+type AdminConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Token, when set, must be sent as "Authorization: Bearer <token>"
+	Token string `json:"token" yaml:"token"`
+}
+
+// DefaultAdminConfig returns default admin configuration
+func DefaultAdminConfig() AdminConfig {
+	return AdminConfig{
+		Enabled: false,
+	}
+}
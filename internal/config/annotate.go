@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// optionComments describes every configuration option, keyed by its dotted YAML path
+var optionComments = map[string]string{
+	"server":                         "Server settings",
+	"server.host":                    "Host to run the mock server on",
+	"server.port":                    "Port to run the mock server on",
+	"server.example_rotation":        "How named examples are chosen: first, roundrobin, or random",
+	"server.warmup_delay":            "Readiness and spec routes return 503 until this delay has elapsed",
+	"server.response_delay":          "Baseline latency added to every response except /health and /ready",
+	"server.max_query_length":        "Reject requests whose query string exceeds this many bytes with 414 (0 disables)",
+	"server.route_max_request_size":  "Request body limit in bytes per operation, e.g. { \"POST /uploads\": 52428800 }",
+	"server.hal_links":               "Add a HAL-style _links object built from the response's OpenAPI links",
+	"server.cache_debug":             "Send X-Mock-Cache (HIT or MISS) and a hashed X-Mock-Cache-Key on mock responses",
+	"server.collapse_2xx":            "Send every generated 2xx response as 200, keeping the body, for clients that only handle 200",
+	"server.error_format":            "problem_json, or a JSON template for the mock's own error responses with {{status}}, {{message}}, and {{methods}} placeholders",
+	"server.raw_spec_path":           "Serve the spec file byte-for-byte at this path, e.g. /openapi.yaml (empty disables)",
+	"server.compression":             "Gzip responses for clients that send Accept-Encoding: gzip",
+	"server.compression_level":       "Gzip level from 1 (fastest) to 9 (smallest); 0 uses the default",
+	"server.method_override":         "Route POST requests as the method named in X-HTTP-Method-Override",
+	"server.auto_head":               "Answer HEAD on paths without a HEAD operation with the GET response's headers and no body",
+	"server.shutdown_timeout":        "How long in-flight requests may finish on SIGTERM before they are cancelled",
+	"server.read_timeout":            "Maximum time to read a request, including its body",
+	"server.write_timeout":           "Maximum time to write a response; raise it for long polling or streaming",
+	"server.idle_timeout":            "How long an idle keep-alive connection stays open",
+	"server.disable_keep_alives":     "Close every connection after one response",
+	"server.generation_timeout":      "Answer 503 when generating a response takes longer than this; 0 disables the check",
+	"server.allow_response_override": "Serve the X-Mock-Response request header as the response body; for testing only",
+	"server.use_server_base_path":    "Serve routes under the path of each of the spec's servers, e.g. /api/v1/pets",
+	"server.strict_slash":            "Treat /pets/ and /pets as different paths; false serves both from /pets",
+	"server.case_insensitive_paths":  "Match spec paths regardless of case, e.g. /Pets for /pets",
+	"server.strip_path_prefix":       "Remove this prefix from request paths before routing, e.g. /mock serves /mock/pets from /pets",
+	"server.add_path_prefix":         "Add this prefix to request paths before routing, e.g. /api serves /pets from /api/pets",
+
+	"security":                        "Security settings",
+	"security.cors":                   "Cross-origin resource sharing",
+	"security.cors.enabled":           "Enable CORS headers",
+	"security.cors.allowed_origins":   "Origins allowed to call the mock",
+	"security.cors.allowed_methods":   "HTTP methods allowed for cross-origin requests",
+	"security.cors.allowed_headers":   "Request headers allowed for cross-origin requests",
+	"security.cors.allow_credentials": "Allow credentials on cross-origin requests",
+	"security.cors.max_age":           "Seconds browsers may cache preflight responses (0 turns caching off)",
+	"security.cors.preflight_status":  "Status returned for preflight OPTIONS requests: 200 or 204",
+	"security.cors.routes":            "Per-path overrides of the CORS settings, keyed by OpenAPI path",
+
+	"observability":                      "Observability settings",
+	"observability.logging":              "Structured logging",
+	"observability.logging.level":        "Log level: debug, info, warn, or error",
+	"observability.logging.format":       "Log format: json or console",
+	"observability.logging.output":       "Log output: stdout, stderr, or a file path",
+	"observability.logging.development":  "Enable zap development mode",
+	"observability.tracing":              "OpenTelemetry tracing exported over OTLP/HTTP",
+	"observability.tracing.enabled":      "Create a span per request and export it",
+	"observability.tracing.endpoint":     "OTLP/HTTP collector URL; http:// disables TLS",
+	"observability.tracing.service_name": "service.name resource attribute on exported spans",
+
+	"spec_file":  "Path to the OpenAPI specification file",
+	"expand_env": "Replace ${VAR} tokens in the spec with environment variables",
+	"strict_env": "Fail to load the spec when an expanded variable is not set",
+	"strict":     "Fail to load a spec that defines no operations instead of warning",
+
+	"hot_reload":           "Reload the specification when it changes",
+	"hot_reload.enabled":   "Enable hot reload",
+	"hot_reload.debounce":  "Wait this long after a change before reloading",
+	"hot_reload.max_stale": "Report 503 from /ready once reloads have failed for this long (0 disables)",
+	"hot_reload.watch_dir": "Also reload when a .yaml, .yml, or .json file in this directory changes",
+
+	"proxy":                   "Forward requests without a mock route to a real backend",
+	"proxy.enabled":           "Enable proxy fallback",
+	"proxy.target":            "Backend URL, required when the proxy is enabled",
+	"proxy.timeout":           "Upstream request timeout",
+	"proxy.path_prefixes":     "Only proxy paths under these prefixes, e.g. [/api/legacy]; other unmatched paths return 404. Empty proxies everything",
+	"proxy.record_dir":        "Directory holding recordings.jsonl; proxied traffic is appended to it in live mode (empty disables)",
+	"proxy.mode":              "live forwards to the target; replay serves recordings from record_dir",
+	"proxy.replay_fallback":   "In replay mode, forward requests without a recording to the target instead of returning 404",
+	"proxy.replay_match_body": "In replay mode, also match recordings by a hash of the request body",
+	"proxy.health_check":      "Report /ready as not ready while the target cannot be reached",
+
+	"tls":           "HTTPS settings",
+	"tls.enabled":   "Serve over HTTPS only",
+	"tls.cert_file": "Certificate file; leave both files empty to use a generated self-signed certificate",
+	"tls.key_file":  "Private key file",
+
+	"scenario":          "Select named examples from a request header",
+	"scenario.header":   "Request header consulted for scenario selection",
+	"scenario.examples": "Header value to named example, e.g. { empty: emptyList }",
+
+	"language":          "Select named examples from the Accept-Language header",
+	"language.examples": "Language tag to named example, e.g. { fr: french }",
+
+	"body_matching":       "Select named examples from values in JSON request bodies",
+	"body_matching.rules": "Checked in order: {operation, path: $.type, equals: premium, example: premiumAccount}",
+
+	"generator":                          "Schema-based data generation",
+	"generator.default_array_length":     "Items generated for arrays without minItems or maxItems",
+	"generator.max_array_length":         "Upper bound on generated array length",
+	"generator.map_entries":              "Keys generated for additionalProperties map schemas",
+	"generator.null_probability":         "Chance (0-1) of generating null for nullable fields",
+	"generator.boolean_true_probability": "Chance (0-1) of generating true for booleans without a field-name bias",
+	"generator.stable_uuids":             "Generate the same UUIDs on every request for the same path parameters",
+
+	"admin":         "Runtime admin endpoints under /admin",
+	"admin.enabled": "Enable admin endpoints",
+	"admin.token":   "Require Authorization: Bearer <token> on admin endpoints; /admin/reload is refused while empty",
+
+	"maintenance":             "Maintenance mode",
+	"maintenance.enabled":     "Start with every spec route returning the maintenance response",
+	"maintenance.status_code": "Status code returned during maintenance",
+	"maintenance.retry_after": "Retry-After hint sent during maintenance",
+	"maintenance.message":     "Error message returned during maintenance",
+
+	"outages":             "Simulated partial outages",
+	"outages.status_code": "Status code returned by disabled operations",
+	"outages.operations":  "Disabled operations, by operationId or \"METHOD /path\"",
+
+	"idempotency":          "Replay the first response to a POST or PATCH for each Idempotency-Key",
+	"idempotency.enabled":  "Honor the Idempotency-Key request header",
+	"idempotency.ttl":      "How long a key's response is replayed",
+	"idempotency.max_keys": "Keys remembered at once; the oldest are evicted first",
+
+	"sessions":             "Cookie-based session simulation; operations are operationIds or \"METHOD /path\"",
+	"sessions.enabled":     "Issue a session cookie on login and require it on protected operations",
+	"sessions.cookie_name": "Name of the session cookie",
+	"sessions.login":       "Operation whose responses set a new session cookie",
+	"sessions.logout":      "Operation that ends the session and clears the cookie (optional)",
+	"sessions.protected":   "Operations that return 401 without a valid session cookie",
+	"sessions.ttl":         "How long a session stays valid",
+
+	"cache":             "Cache of generated responses",
+	"cache.enabled":     "Reuse generated responses for identical requests; disable to regenerate data every time",
+	"cache.max_entries": "Responses kept at once; the least recently used are evicted first (0 is unbounded)",
+	"cache.warmup":      "Generate every defined response at startup and on reload, so first requests skip generation",
+
+	"echo":                "Reflect requests back as JSON instead of the mocked response",
+	"echo.enabled":        "Echo every spec route; a single request opts in with __echo=true",
+	"echo.redact_headers": "Request headers whose values are replaced with [REDACTED] in echoes",
+
+	"response":                "How JSON response bodies are written",
+	"response.pretty":         "Indent JSON responses for readability",
+	"response.indent":         "Spaces per level when pretty is enabled",
+	"response.preserve_order": "Write object properties in the order the spec declares them",
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// MarshalAnnotatedYAML renders the configuration as YAML with a comment above every option
+func MarshalAnnotatedYAML(cfg *Config) ([]byte, error) {
+	root, err := annotatedNode(reflect.ValueOf(cfg).Elem(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{
+		Kind:        yaml.DocumentNode,
+		HeadComment: "Go-Spec-Mock configuration. Values shown are the defaults.",
+		Content:     []*yaml.Node{root},
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// annotatedNode converts a configuration value into a YAML node, attaching the
+// option comment to every key of nested structs
+func annotatedNode(v reflect.Value, path string) (*yaml.Node, error) {
+	if v.Type() == durationType {
+		// Durations are written in Go duration syntax rather than as nanoseconds
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: time.Duration(v.Int()).String()}, nil
+	}
+
+	if v.Kind() != reflect.Struct {
+		node := &yaml.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		return node, nil
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		value, err := annotatedNode(v.Field(i), fieldPath)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: name, HeadComment: optionComments[fieldPath]}
+		mapping.Content = append(mapping.Content, key, value)
+	}
+	return mapping, nil
+}
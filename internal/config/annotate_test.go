@@ -0,0 +1,54 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalAnnotatedYAML_RoundTrip(t *testing.T) {
+	data, err := MarshalAnnotatedYAML(DefaultConfig())
+	if err != nil {
+		t.Fatalf("MarshalAnnotatedYAML() error = %v", err)
+	}
+
+	loaded := &Config{}
+	if err := yaml.Unmarshal(data, loaded); err != nil {
+		t.Fatalf("Generated YAML does not parse: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(loaded, DefaultConfig()) {
+		t.Errorf("Generated YAML does not round-trip to the defaults:\n%s", data)
+	}
+
+	output := string(data)
+	for _, want := range []string{"debounce: 500ms", "# Host to run the mock server on", "    # Log level: debug, info, warn, or error"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestMarshalAnnotatedYAML_EveryOptionCommented(t *testing.T) {
+	var check func(typ reflect.Type, prefix string)
+	check = func(typ reflect.Type, prefix string) {
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			if optionComments[path] == "" {
+				t.Errorf("Missing comment for configuration option %s", path)
+			}
+			if field := typ.Field(i).Type; field.Kind() == reflect.Struct && field != durationType {
+				check(field, path)
+			}
+		}
+	}
+	check(reflect.TypeOf(Config{}), "")
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BodyMatchConfig selects named examples from values in JSON request bodies
+type BodyMatchConfig struct {
+	Rules []BodyMatchRule `json:"rules" yaml:"rules"`
+}
+
+// BodyMatchRule selects Example for requests to Operation whose body has the
+// value Equals at Path
+type BodyMatchRule struct {
+	// Operation is an operationId or "METHOD /path"
+	Operation string `json:"operation" yaml:"operation"`
+	// Path locates the value in the body, e.g. $.type or $.items[0].kind
+	Path    string `json:"path" yaml:"path"`
+	Equals  string `json:"equals" yaml:"equals"`
+	Example string `json:"example" yaml:"example"`
+}
+
+// DefaultBodyMatchConfig returns default body matching configuration
+func DefaultBodyMatchConfig() BodyMatchConfig {
+	return BodyMatchConfig{Rules: []BodyMatchRule{}}
+}
+
+// Validate validates the body matching configuration
+func (b BodyMatchConfig) Validate() error {
+	for i, rule := range b.Rules {
+		if strings.TrimSpace(rule.Operation) == "" {
+			return fmt.Errorf("body_matching rule %d must name an operation", i+1)
+		}
+		if rule.Example == "" {
+			return fmt.Errorf("body_matching rule %d must map to a named example", i+1)
+		}
+		if _, err := ParseJSONPath(rule.Path); err != nil {
+			return fmt.Errorf("body_matching rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ParseJSONPath splits a JSONPath-like expression such as $.items[0].kind into
+// its property names and array indexes. Only dot-separated names and numeric
+// indexes are supported.
+func ParseJSONPath(path string) ([]string, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+
+	var segments []string
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("path %q has an empty property name", path)
+			}
+			segments = append(segments, name)
+			rest = rest[end+1:]
+		case '[':
+			index, after, ok := strings.Cut(rest[1:], "]")
+			if _, err := strconv.Atoi(index); !ok || err != nil {
+				return nil, fmt.Errorf("path %q has an invalid array index", path)
+			}
+			segments = append(segments, index)
+			rest = after
+		default:
+			return nil, fmt.Errorf("path %q is not a supported JSONPath expression", path)
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path %q must select a value inside the body", path)
+	}
+	return segments, nil
+}
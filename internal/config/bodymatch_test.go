@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{path: "$.plan", want: []string{"plan"}},
+		{path: "$.customer.tier", want: []string{"customer", "tier"}},
+		{path: "$.items[0].sku", want: []string{"items", "0", "sku"}},
+		{path: "plan", wantErr: true},
+		{path: "$", wantErr: true},
+		{path: "$..plan", wantErr: true},
+		{path: "$.items[x]", wantErr: true},
+		{path: "$.items[0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseJSONPath(tt.path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseJSONPath(%q): expected error, got %v", tt.path, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseJSONPath(%q): unexpected error: %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBodyMatchConfigValidate(t *testing.T) {
+	if err := DefaultBodyMatchConfig().Validate(); err != nil {
+		t.Fatalf("expected default body matching config to be valid, got %v", err)
+	}
+
+	valid := BodyMatchConfig{Rules: []BodyMatchRule{{Operation: "createOrder", Path: "$.plan", Equals: "premium", Example: "premiumOrder"}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected rule to be valid, got %v", err)
+	}
+
+	invalid := []BodyMatchRule{
+		{Path: "$.plan", Example: "premiumOrder"},
+		{Operation: "createOrder", Path: "$.plan"},
+		{Operation: "createOrder", Path: "plan", Example: "premiumOrder"},
+	}
+	for _, rule := range invalid {
+		cfg := BodyMatchConfig{Rules: []BodyMatchRule{rule}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for rule %+v", rule)
+		}
+	}
+}
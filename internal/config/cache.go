@@ -0,0 +1,45 @@
+package config
+
+import "fmt"
+
+// CacheConfig contains configuration for the generated response cache
+type CacheConfig struct {
+	// Enabled is a pointer so that an explicit false in a config file disables caching
+	Enabled *bool `json:"enabled" yaml:"enabled"`
+	// MaxEntries bounds the cache; the least recently used responses are evicted
+	// first. It is a pointer so that an explicit 0 in a config file is unbounded.
+	MaxEntries *int `json:"max_entries" yaml:"max_entries"`
+	// Warmup generates every defined response at startup and on reload
+	Warmup bool `json:"warmup" yaml:"warmup"`
+}
+
+// DefaultCacheMaxEntries is the default number of cached responses
+const DefaultCacheMaxEntries = 10000
+
+// DefaultCacheConfig returns default cache configuration
+func DefaultCacheConfig() CacheConfig {
+	enabled := true
+	maxEntries := DefaultCacheMaxEntries
+	return CacheConfig{Enabled: &enabled, MaxEntries: &maxEntries}
+}
+
+// EntryLimit returns the maximum number of cached responses, 0 for unbounded
+func (c CacheConfig) EntryLimit() int {
+	if c.MaxEntries == nil {
+		return DefaultCacheMaxEntries
+	}
+	return *c.MaxEntries
+}
+
+// IsEnabled reports whether responses are cached; caching is on unless explicitly disabled
+func (c CacheConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// Validate validates the cache configuration
+func (c CacheConfig) Validate() error {
+	if c.EntryLimit() < 0 {
+		return fmt.Errorf("cache max_entries must be non-negative")
+	}
+	return nil
+}
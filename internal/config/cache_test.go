@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestLoadConfig_CacheMaxEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expected   int
+	}{
+		{name: "default when unset", configFile: "cache:\n  enabled: true\n", expected: DefaultCacheMaxEntries},
+		{name: "explicit zero is unbounded", configFile: "cache:\n  max_entries: 0\n", expected: 0},
+		{name: "explicit value", configFile: "cache:\n  max_entries: 50\n", expected: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(writeTempConfig(t, tt.configFile), nil)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if got := config.Cache.EntryLimit(); got != tt.expected {
+				t.Errorf("Expected max_entries %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
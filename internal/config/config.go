@@ -10,9 +10,24 @@ type Config struct {
 	Security      SecurityConfig      `json:"security" yaml:"security"`
 	Observability ObservabilityConfig `json:"observability" yaml:"observability"`
 	SpecFile      string              `json:"spec_file" yaml:"spec_file"`
+	ExpandEnv     bool                `json:"expand_env" yaml:"expand_env"`
+	StrictEnv     bool                `json:"strict_env" yaml:"strict_env"`
+	Strict        bool                `json:"strict" yaml:"strict"`
 	HotReload     HotReloadConfig     `json:"hot_reload" yaml:"hot_reload"`
 	Proxy         ProxyConfig         `json:"proxy" yaml:"proxy"`
 	TLS           TLSConfig           `json:"tls" yaml:"tls"`
+	Scenario      ScenarioConfig      `json:"scenario" yaml:"scenario"`
+	BodyMatching  BodyMatchConfig     `json:"body_matching" yaml:"body_matching"`
+	Language      LanguageConfig      `json:"language" yaml:"language"`
+	Generator     GeneratorConfig     `json:"generator" yaml:"generator"`
+	Admin         AdminConfig         `json:"admin" yaml:"admin"`
+	Maintenance   MaintenanceConfig   `json:"maintenance" yaml:"maintenance"`
+	Outages       OutageConfig        `json:"outages" yaml:"outages"`
+	Idempotency   IdempotencyConfig   `json:"idempotency" yaml:"idempotency"`
+	Sessions      SessionConfig       `json:"sessions" yaml:"sessions"`
+	Cache         CacheConfig         `json:"cache" yaml:"cache"`
+	Echo          EchoConfig          `json:"echo" yaml:"echo"`
+	Response      ResponseConfig      `json:"response" yaml:"response"`
 }
 
 // DefaultConfig returns the default configuration
@@ -25,6 +40,18 @@ func DefaultConfig() *Config {
 		HotReload:     DefaultHotReloadConfig(),
 		Proxy:         DefaultProxyConfig(),
 		TLS:           DefaultTLSConfig(),
+		Scenario:      DefaultScenarioConfig(),
+		BodyMatching:  DefaultBodyMatchConfig(),
+		Language:      DefaultLanguageConfig(),
+		Generator:     DefaultGeneratorConfig(),
+		Admin:         DefaultAdminConfig(),
+		Maintenance:   DefaultMaintenanceConfig(),
+		Outages:       DefaultOutageConfig(),
+		Idempotency:   DefaultIdempotencyConfig(),
+		Sessions:      DefaultSessionConfig(),
+		Cache:         DefaultCacheConfig(),
+		Echo:          DefaultEchoConfig(),
+		Response:      DefaultResponseConfig(),
 	}
 }
 
@@ -48,5 +75,38 @@ func (c *Config) Validate() error {
 	if err := c.TLS.Validate(); err != nil {
 		return fmt.Errorf("tls config validation failed: %w", err)
 	}
+	if err := c.Scenario.Validate(); err != nil {
+		return fmt.Errorf("scenario config validation failed: %w", err)
+	}
+	if err := c.BodyMatching.Validate(); err != nil {
+		return fmt.Errorf("body_matching config validation failed: %w", err)
+	}
+	if err := c.Language.Validate(); err != nil {
+		return fmt.Errorf("language config validation failed: %w", err)
+	}
+	if err := c.Generator.Validate(); err != nil {
+		return fmt.Errorf("generator config validation failed: %w", err)
+	}
+	if err := c.Maintenance.Validate(); err != nil {
+		return fmt.Errorf("maintenance config validation failed: %w", err)
+	}
+	if err := c.Outages.Validate(); err != nil {
+		return fmt.Errorf("outages config validation failed: %w", err)
+	}
+	if err := c.Idempotency.Validate(); err != nil {
+		return fmt.Errorf("idempotency config validation failed: %w", err)
+	}
+	if err := c.Sessions.Validate(); err != nil {
+		return fmt.Errorf("sessions config validation failed: %w", err)
+	}
+	if err := c.Cache.Validate(); err != nil {
+		return fmt.Errorf("cache config validation failed: %w", err)
+	}
+	if err := c.Echo.Validate(); err != nil {
+		return fmt.Errorf("echo config validation failed: %w", err)
+	}
+	if err := c.Response.Validate(); err != nil {
+		return fmt.Errorf("response config validation failed: %w", err)
+	}
 	return nil
 }
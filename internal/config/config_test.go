@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -13,7 +14,7 @@ func TestDefaultConfig(t *testing.T) {
 	}
 
 	// Check if sub-configs are also initialized
-	if cfg.Server == (ServerConfig{}) {
+	if reflect.DeepEqual(cfg.Server, ServerConfig{}) {
 		t.Error("DefaultConfig did not initialize ServerConfig")
 	}
 
@@ -34,6 +35,7 @@ func TestConfig_Validate(t *testing.T) {
 				Server:        DefaultServerConfig(),
 				Security:      DefaultSecurityConfig(),
 				Observability: DefaultObservabilityConfig(),
+				Generator:     DefaultGeneratorConfig(),
 				SpecFile:      "test.yaml",
 				TLS:           DefaultTLSConfig(),
 			},
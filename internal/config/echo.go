@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+)
+
+// EchoConfig contains configuration for reflecting requests back as responses
+type EchoConfig struct {
+	Enabled       bool     `json:"enabled" yaml:"enabled"`
+	RedactHeaders []string `json:"redact_headers" yaml:"redact_headers"`
+}
+
+// DefaultEchoConfig returns default echo configuration
+func DefaultEchoConfig() EchoConfig {
+	return EchoConfig{
+		Enabled:       false,
+		RedactHeaders: []string{"Authorization", "Cookie", "Proxy-Authorization"},
+	}
+}
+
+// Validate validates the echo configuration
+func (e EchoConfig) Validate() error {
+	for _, header := range e.RedactHeaders {
+		if header == "" {
+			return fmt.Errorf("echo redact_headers cannot contain empty entries")
+		}
+	}
+	return nil
+}
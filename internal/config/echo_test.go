@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestEchoConfigValidate(t *testing.T) {
+	if err := (EchoConfig{}).Validate(); err != nil {
+		t.Fatalf("expected zero-value config to be valid, got %v", err)
+	}
+	if err := DefaultEchoConfig().Validate(); err != nil {
+		t.Fatalf("expected default config to be valid, got %v", err)
+	}
+
+	cfg := DefaultEchoConfig()
+	cfg.RedactHeaders = []string{""}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for empty redact_headers entry")
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Placeholders substituted into server.error_format
+const (
+	ErrorFormatStatus  = "{{status}}"
+	ErrorFormatMessage = "{{message}}"
+	ErrorFormatMethods = "{{methods}}"
+)
+
+// ErrorFormatProblemJSON as server.error_format sends RFC 7807
+// application/problem+json errors instead of rendering a template
+const ErrorFormatProblemJSON = "problem_json"
+
+// RenderErrorFormat fills an error_format template. The status is inserted as
+// a number; the message and the comma-separated allowed methods are JSON
+// string-escaped, so the template should quote them, e.g. "{{message}}".
+func RenderErrorFormat(format string, status int, message string, methods []string) string {
+	return strings.NewReplacer(
+		ErrorFormatStatus, strconv.Itoa(status),
+		ErrorFormatMessage, jsonEscape(message),
+		ErrorFormatMethods, jsonEscape(strings.Join(methods, ", ")),
+	).Replace(format)
+}
+
+// validateErrorFormat checks that the template renders valid JSON
+func validateErrorFormat(format string) bool {
+	if format == ErrorFormatProblemJSON {
+		return true
+	}
+	sample := RenderErrorFormat(format, 405, `Method "PATCH" not allowed`, []string{"GET", "POST"})
+	return json.Valid([]byte(sample))
+}
+
+// jsonEscape returns s escaped for use inside a JSON string literal
+func jsonEscape(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted[1 : len(quoted)-1])
+}
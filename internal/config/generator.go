@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Default generation probabilities
+const (
+	DefaultArrayLength            = 2   // Items generated for arrays without size constraints
+	DefaultMapEntries             = 2   // Keys generated for additionalProperties map schemas
+	DefaultNullProbability        = 0.1 // Chance of generating null for nullable schemas
+	DefaultBooleanTrueProbability = 0.5 // Chance of generating true for booleans
+)
+
+// GeneratorConfig contains configuration for schema-based data generation
+type GeneratorConfig struct {
+	// DefaultArrayLength is how many items are generated for arrays without minItems or maxItems
+	DefaultArrayLength int `json:"default_array_length" yaml:"default_array_length"`
+	MaxArrayLength     int `json:"max_array_length" yaml:"max_array_length"`
+	// MapEntries is how many keys are generated for additionalProperties map
+	// schemas; a pointer so that an explicit 0 in a config file yields empty maps
+	MapEntries *int `json:"map_entries" yaml:"map_entries"`
+	// NullProbability is a pointer so that an explicit 0 in a config file disables nulls
+	NullProbability *float64 `json:"null_probability" yaml:"null_probability"`
+	// BooleanTrueProbability is a pointer for the same reason
+	BooleanTrueProbability *float64 `json:"boolean_true_probability" yaml:"boolean_true_probability"`
+	// StableUUIDs derives generated UUIDs from the request's path parameters
+	StableUUIDs bool `json:"stable_uuids" yaml:"stable_uuids"`
+}
+
+// DefaultGeneratorConfig returns default generator configuration
+func DefaultGeneratorConfig() GeneratorConfig {
+	nullProbability := DefaultNullProbability
+	booleanTrueProbability := DefaultBooleanTrueProbability
+	mapEntries := DefaultMapEntries
+	return GeneratorConfig{
+		DefaultArrayLength:     DefaultArrayLength,
+		MaxArrayLength:         1000,
+		MapEntries:             &mapEntries,
+		NullProbability:        &nullProbability,
+		BooleanTrueProbability: &booleanTrueProbability,
+	}
+}
+
+// NullChance returns the configured null probability, or 0 when unset
+func (g GeneratorConfig) NullChance() float64 {
+	if g.NullProbability == nil {
+		return 0
+	}
+	return *g.NullProbability
+}
+
+// Validate validates the generator configuration
+func (g GeneratorConfig) Validate() error {
+	if g.DefaultArrayLength <= 0 {
+		return fmt.Errorf("default_array_length must be positive")
+	}
+	if g.MaxArrayLength < 0 {
+		return fmt.Errorf("max_array_length must be non-negative")
+	}
+	if g.MapEntries != nil && *g.MapEntries < 0 {
+		return fmt.Errorf("map_entries must be non-negative")
+	}
+	if p := g.NullChance(); p < 0 || p > 1 {
+		return fmt.Errorf("null_probability must be between 0 and 1")
+	}
+	if p := g.BooleanTrueProbability; p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("boolean_true_probability must be between 0 and 1")
+	}
+	return nil
+}
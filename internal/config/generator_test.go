@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestGeneratorConfigValidate_NullProbabilityRange(t *testing.T) {
+	for _, p := range []float64{-0.1, 1.5} {
+		cfg := DefaultGeneratorConfig()
+		cfg.NullProbability = &p
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for null_probability %v", p)
+		}
+	}
+}
+
+func TestLoadConfig_NullProbability(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expected   float64
+	}{
+		{name: "default when unset", configFile: "generator:\n  max_array_length: 50\n", expected: DefaultNullProbability},
+		{name: "explicit zero disables nulls", configFile: "generator:\n  null_probability: 0\n", expected: 0},
+		{name: "explicit value", configFile: "generator:\n  null_probability: 0.5\n", expected: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(writeTempConfig(t, tt.configFile), nil)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if got := config.Generator.NullChance(); got != tt.expected {
+				t.Errorf("Expected null probability %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGeneratorConfigValidate_BooleanTrueProbabilityRange(t *testing.T) {
+	p := 1.5
+	cfg := DefaultGeneratorConfig()
+	cfg.BooleanTrueProbability = &p
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for boolean_true_probability 1.5")
+	}
+}
+
+func TestLoadConfig_MapEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expected   int
+	}{
+		{name: "default when unset", configFile: "generator:\n  max_array_length: 50\n", expected: DefaultMapEntries},
+		{name: "explicit zero yields empty maps", configFile: "generator:\n  map_entries: 0\n", expected: 0},
+		{name: "explicit value", configFile: "generator:\n  map_entries: 5\n", expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(writeTempConfig(t, tt.configFile), nil)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if config.Generator.MapEntries == nil || *config.Generator.MapEntries != tt.expected {
+				t.Errorf("Expected map_entries %d, got %v", tt.expected, config.Generator.MapEntries)
+			}
+		})
+	}
+}
@@ -9,6 +9,10 @@ import (
 type HotReloadConfig struct {
 	Enabled  bool          `json:"enabled" yaml:"enabled"`
 	Debounce time.Duration `json:"debounce" yaml:"debounce"`
+	// MaxStale is how long reloads may keep failing before readiness reports 503; 0 disables the check
+	MaxStale time.Duration `json:"max_stale" yaml:"max_stale"`
+	// WatchDir is an extra directory whose spec files also trigger a reload
+	WatchDir string `json:"watch_dir" yaml:"watch_dir"`
 }
 
 // DefaultHotReloadConfig returns default hot reload configuration
@@ -24,5 +28,8 @@ func (h HotReloadConfig) Validate() error {
 	if h.Debounce < 0 {
 		return fmt.Errorf("hot reload debounce time must be non-negative")
 	}
+	if h.MaxStale < 0 {
+		return fmt.Errorf("hot reload max_stale must be non-negative")
+	}
 	return nil
 }
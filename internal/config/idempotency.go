@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// IdempotencyConfig contains configuration for replaying responses to repeated
+// Idempotency-Key requests
+type IdempotencyConfig struct {
+	Enabled bool          `json:"enabled" yaml:"enabled"`
+	TTL     time.Duration `json:"ttl" yaml:"ttl"`
+	MaxKeys int           `json:"max_keys" yaml:"max_keys"`
+}
+
+// DefaultIdempotencyConfig returns default idempotency configuration
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		Enabled: false,
+		TTL:     24 * time.Hour,
+		MaxKeys: 1000,
+	}
+}
+
+// Validate validates the idempotency configuration
+func (i IdempotencyConfig) Validate() error {
+	if i.TTL < 0 {
+		return fmt.Errorf("idempotency ttl must be non-negative")
+	}
+	if i.MaxKeys < 0 {
+		return fmt.Errorf("idempotency max_keys must be non-negative")
+	}
+	if i.Enabled && (i.TTL == 0 || i.MaxKeys == 0) {
+		return fmt.Errorf("idempotency ttl and max_keys must be positive when enabled")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyConfigValidate(t *testing.T) {
+	cfg := DefaultIdempotencyConfig()
+	cfg.Enabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected default idempotency config to be valid, got %v", err)
+	}
+
+	cfg.TTL = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for negative ttl")
+	}
+
+	cfg.TTL = time.Minute
+	cfg.MaxKeys = 0
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for zero max_keys when enabled")
+	}
+
+	if err := (IdempotencyConfig{}).Validate(); err != nil {
+		t.Fatalf("expected zero-value config to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// LanguageConfig maps language tags to named examples, negotiated against the
+// request's Accept-Language header
+type LanguageConfig struct {
+	Examples map[string]string `json:"examples" yaml:"examples"`
+}
+
+// DefaultLanguageConfig returns default language configuration
+func DefaultLanguageConfig() LanguageConfig {
+	return LanguageConfig{
+		Examples: map[string]string{},
+	}
+}
+
+// Validate validates the language configuration
+func (l LanguageConfig) Validate() error {
+	for tag, exampleName := range l.Examples {
+		if _, err := language.Parse(tag); err != nil {
+			return fmt.Errorf("language %q is not a valid language tag: %w", tag, err)
+		}
+		if exampleName == "" {
+			return fmt.Errorf("language %q must map to a named example", tag)
+		}
+	}
+	return nil
+}
+
+// Tags returns the configured language tags in a stable order
+func (l LanguageConfig) Tags() []string {
+	tags := make([]string, 0, len(l.Examples))
+	for tag := range l.Examples {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
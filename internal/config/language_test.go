@@ -0,0 +1,35 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLanguageConfigValidate(t *testing.T) {
+	if err := DefaultLanguageConfig().Validate(); err != nil {
+		t.Fatalf("expected default language config to be valid, got %v", err)
+	}
+
+	valid := LanguageConfig{Examples: map[string]string{"fr": "french", "pt-BR": "portuguese"}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected language config to be valid, got %v", err)
+	}
+
+	invalid := []map[string]string{
+		{"not a tag!": "french"},
+		{"fr": ""},
+	}
+	for _, examples := range invalid {
+		cfg := LanguageConfig{Examples: examples}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for %v", examples)
+		}
+	}
+}
+
+func TestLanguageConfigTags(t *testing.T) {
+	cfg := LanguageConfig{Examples: map[string]string{"fr": "french", "de": "german", "en": "english"}}
+	if got, want := cfg.Tags(), []string{"de", "en", "fr"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected tags %v, got %v", want, got)
+	}
+}
@@ -56,11 +56,13 @@ type CLIFlags struct {
 	MetricsPort  *string
 	SpecFile     *string
 	HotReload    *bool
+	WatchDir     *string
 	ProxyEnabled *bool
 	ProxyTarget  *string
 	TLSEnabled   *bool
 	TLSCertFile  *string
 	TLSKeyFile   *string
+	ArrayLength  *int
 }
 
 // loadFromFile loads configuration from a YAML or JSON file
@@ -117,6 +119,14 @@ func setBoolFromEnv(envVar string, target *bool) {
 	}
 }
 
+func setIntFromEnv(envVar string, target *int) {
+	if val := os.Getenv(envVar); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			*target = n
+		}
+	}
+}
+
 func setDurationFromEnv(envVar string, target *time.Duration) {
 	if val := os.Getenv(envVar); val != "" {
 		if duration, err := time.ParseDuration(val); err == nil {
@@ -130,6 +140,10 @@ func loadFromEnv(config *Config) {
 	// Server configuration
 	setStringFromEnv(constants.EnvHost, &config.Server.Host)
 	setStringFromEnv(constants.EnvPort, &config.Server.Port)
+	setDurationFromEnv(constants.EnvShutdownTimeout, &config.Server.ShutdownTimeout)
+	setDurationFromEnv(constants.EnvReadTimeout, &config.Server.ReadTimeout)
+	setDurationFromEnv(constants.EnvWriteTimeout, &config.Server.WriteTimeout)
+	setDurationFromEnv(constants.EnvIdleTimeout, &config.Server.IdleTimeout)
 
 	// Spec file and hot reload
 	setStringFromEnv(constants.EnvSpecFile, &config.SpecFile)
@@ -145,6 +159,12 @@ func loadFromEnv(config *Config) {
 	setBoolFromEnv(constants.EnvTLSEnabled, &config.TLS.Enabled)
 	setStringFromEnv(constants.EnvTLSCertFile, &config.TLS.CertFile)
 	setStringFromEnv(constants.EnvTLSKeyFile, &config.TLS.KeyFile)
+
+	// Admin configuration
+	setStringFromEnv(constants.EnvAdminToken, &config.Admin.Token)
+
+	// Generator configuration
+	setIntFromEnv(constants.EnvArrayLength, &config.Generator.DefaultArrayLength)
 }
 
 // Helper functions for CLI flag overrides
@@ -160,6 +180,12 @@ func setBoolFromCLI(flagValue *bool, flagName string, target *bool) {
 	}
 }
 
+func setIntFromCLI(flagValue *int, flagName string, target *int) {
+	if flagValue != nil && isFlagSet(flagName) {
+		*target = *flagValue
+	}
+}
+
 // overrideWithCLI overrides configuration with CLI flag values
 // Only explicitly set CLI flags override other configuration sources
 func overrideWithCLI(config *Config, flags *CLIFlags) {
@@ -174,6 +200,7 @@ func overrideWithCLI(config *Config, flags *CLIFlags) {
 	// Spec file and hot reload
 	setStringFromCLI(flags.SpecFile, "spec-file", &config.SpecFile)
 	setBoolFromCLI(flags.HotReload, "hot-reload", &config.HotReload.Enabled)
+	setStringFromCLI(flags.WatchDir, "watch-dir", &config.HotReload.WatchDir)
 
 	// Proxy configuration
 	setBoolFromCLI(flags.ProxyEnabled, "proxy-enabled", &config.Proxy.Enabled)
@@ -183,6 +210,9 @@ func overrideWithCLI(config *Config, flags *CLIFlags) {
 	setBoolFromCLI(flags.TLSEnabled, "tls-enabled", &config.TLS.Enabled)
 	setStringFromCLI(flags.TLSCertFile, "tls-cert-file", &config.TLS.CertFile)
 	setStringFromCLI(flags.TLSKeyFile, "tls-key-file", &config.TLS.KeyFile)
+
+	// Generator configuration
+	setIntFromCLI(flags.ArrayLength, "array-length", &config.Generator.DefaultArrayLength)
 }
 
 // isFlagSet checks if a flag is set (changed) in pflag, or returns true if pflag is not initialized
@@ -208,6 +238,84 @@ func mergeConfig(base *Config, file *Config) {
 	if file.Server.Port != "" {
 		base.Server.Port = file.Server.Port
 	}
+	if file.Server.ExampleRotation != "" {
+		base.Server.ExampleRotation = file.Server.ExampleRotation
+	}
+	if file.Server.WarmupDelay != 0 {
+		base.Server.WarmupDelay = file.Server.WarmupDelay
+	}
+	if file.Server.ResponseDelay != 0 {
+		base.Server.ResponseDelay = file.Server.ResponseDelay
+	}
+	if file.Server.MaxQueryLength != nil {
+		base.Server.MaxQueryLength = file.Server.MaxQueryLength
+	}
+	if len(file.Server.RouteMaxRequestSize) > 0 {
+		base.Server.RouteMaxRequestSize = file.Server.RouteMaxRequestSize
+	}
+	if file.Server.HALLinks {
+		base.Server.HALLinks = true
+	}
+	if file.Server.CacheDebug {
+		base.Server.CacheDebug = true
+	}
+	if file.Server.Collapse2xx {
+		base.Server.Collapse2xx = true
+	}
+	if file.Server.ErrorFormat != "" {
+		base.Server.ErrorFormat = file.Server.ErrorFormat
+	}
+	if file.Server.RawSpecPath != "" {
+		base.Server.RawSpecPath = file.Server.RawSpecPath
+	}
+	if file.Server.Compression {
+		base.Server.Compression = true
+	}
+	if file.Server.CompressionLevel != 0 {
+		base.Server.CompressionLevel = file.Server.CompressionLevel
+	}
+	if file.Server.MethodOverride {
+		base.Server.MethodOverride = true
+	}
+	if file.Server.AutoHead {
+		base.Server.AutoHead = true
+	}
+	if file.Server.ShutdownTimeout > 0 {
+		base.Server.ShutdownTimeout = file.Server.ShutdownTimeout
+	}
+	if file.Server.ReadTimeout > 0 {
+		base.Server.ReadTimeout = file.Server.ReadTimeout
+	}
+	if file.Server.WriteTimeout > 0 {
+		base.Server.WriteTimeout = file.Server.WriteTimeout
+	}
+	if file.Server.IdleTimeout > 0 {
+		base.Server.IdleTimeout = file.Server.IdleTimeout
+	}
+	if file.Server.DisableKeepAlives {
+		base.Server.DisableKeepAlives = true
+	}
+	if file.Server.GenerationTimeout > 0 {
+		base.Server.GenerationTimeout = file.Server.GenerationTimeout
+	}
+	if file.Server.AllowResponseOverride {
+		base.Server.AllowResponseOverride = true
+	}
+	if file.Server.UseServerBasePath {
+		base.Server.UseServerBasePath = true
+	}
+	if file.Server.StrictSlash != nil {
+		base.Server.StrictSlash = file.Server.StrictSlash
+	}
+	if file.Server.CaseInsensitivePaths {
+		base.Server.CaseInsensitivePaths = true
+	}
+	if file.Server.StripPathPrefix != "" {
+		base.Server.StripPathPrefix = file.Server.StripPathPrefix
+	}
+	if file.Server.AddPathPrefix != "" {
+		base.Server.AddPathPrefix = file.Server.AddPathPrefix
+	}
 
 	// Merge observability configuration
 	if file.Observability.Logging.Level != "" {
@@ -219,10 +327,28 @@ func mergeConfig(base *Config, file *Config) {
 	if file.Observability.Logging.Output != "" {
 		base.Observability.Logging.Output = file.Observability.Logging.Output
 	}
+	if file.Observability.Tracing.Enabled {
+		base.Observability.Tracing.Enabled = true
+	}
+	if file.Observability.Tracing.Endpoint != "" {
+		base.Observability.Tracing.Endpoint = file.Observability.Tracing.Endpoint
+	}
+	if file.Observability.Tracing.ServiceName != "" {
+		base.Observability.Tracing.ServiceName = file.Observability.Tracing.ServiceName
+	}
 
 	if file.SpecFile != "" {
 		base.SpecFile = file.SpecFile
 	}
+	if file.ExpandEnv {
+		base.ExpandEnv = true
+	}
+	if file.StrictEnv {
+		base.StrictEnv = true
+	}
+	if file.Strict {
+		base.Strict = true
+	}
 
 	// Merge hot reload configuration
 	if file.HotReload.Enabled != base.HotReload.Enabled {
@@ -231,6 +357,12 @@ func mergeConfig(base *Config, file *Config) {
 	if file.HotReload.Debounce > 0 {
 		base.HotReload.Debounce = file.HotReload.Debounce
 	}
+	if file.HotReload.MaxStale > 0 {
+		base.HotReload.MaxStale = file.HotReload.MaxStale
+	}
+	if file.HotReload.WatchDir != "" {
+		base.HotReload.WatchDir = file.HotReload.WatchDir
+	}
 
 	// Merge TLS configuration
 	if file.TLS.Enabled != base.TLS.Enabled {
@@ -253,6 +385,147 @@ func mergeConfig(base *Config, file *Config) {
 	if file.Proxy.Timeout > 0 {
 		base.Proxy.Timeout = file.Proxy.Timeout
 	}
+	if len(file.Proxy.PathPrefixes) > 0 {
+		base.Proxy.PathPrefixes = file.Proxy.PathPrefixes
+	}
+	if file.Proxy.RecordDir != "" {
+		base.Proxy.RecordDir = file.Proxy.RecordDir
+	}
+	if file.Proxy.Mode != "" {
+		base.Proxy.Mode = file.Proxy.Mode
+	}
+	if file.Proxy.ReplayFallback {
+		base.Proxy.ReplayFallback = true
+	}
+	if file.Proxy.HealthCheck {
+		base.Proxy.HealthCheck = true
+	}
+	if file.Proxy.ReplayMatchBody {
+		base.Proxy.ReplayMatchBody = true
+	}
+
+	// Merge scenario configuration
+	if file.Scenario.Header != "" {
+		base.Scenario.Header = file.Scenario.Header
+	}
+	if len(file.Scenario.Examples) > 0 {
+		base.Scenario.Examples = file.Scenario.Examples
+	}
+	if len(file.Language.Examples) > 0 {
+		base.Language.Examples = file.Language.Examples
+	}
+	if len(file.BodyMatching.Rules) > 0 {
+		base.BodyMatching.Rules = file.BodyMatching.Rules
+	}
+
+	// Merge generator configuration
+	if file.Generator.DefaultArrayLength != 0 {
+		base.Generator.DefaultArrayLength = file.Generator.DefaultArrayLength
+	}
+	if file.Generator.MaxArrayLength > 0 {
+		base.Generator.MaxArrayLength = file.Generator.MaxArrayLength
+	}
+	if file.Generator.MapEntries != nil {
+		base.Generator.MapEntries = file.Generator.MapEntries
+	}
+	if file.Generator.NullProbability != nil {
+		base.Generator.NullProbability = file.Generator.NullProbability
+	}
+	if file.Generator.BooleanTrueProbability != nil {
+		base.Generator.BooleanTrueProbability = file.Generator.BooleanTrueProbability
+	}
+	if file.Generator.StableUUIDs {
+		base.Generator.StableUUIDs = true
+	}
+
+	// Merge admin and maintenance configuration
+	if file.Admin.Enabled {
+		base.Admin.Enabled = file.Admin.Enabled
+	}
+	if file.Admin.Token != "" {
+		base.Admin.Token = file.Admin.Token
+	}
+	if file.Maintenance.Enabled {
+		base.Maintenance.Enabled = file.Maintenance.Enabled
+	}
+	if file.Maintenance.StatusCode != 0 {
+		base.Maintenance.StatusCode = file.Maintenance.StatusCode
+	}
+	if file.Maintenance.RetryAfter > 0 {
+		base.Maintenance.RetryAfter = file.Maintenance.RetryAfter
+	}
+	if file.Maintenance.Message != "" {
+		base.Maintenance.Message = file.Maintenance.Message
+	}
+
+	// Merge outage configuration
+	if file.Outages.StatusCode != 0 {
+		base.Outages.StatusCode = file.Outages.StatusCode
+	}
+	if len(file.Outages.Operations) > 0 {
+		base.Outages.Operations = file.Outages.Operations
+	}
+
+	// Merge idempotency configuration
+	if file.Idempotency.Enabled {
+		base.Idempotency.Enabled = true
+	}
+	if file.Idempotency.TTL > 0 {
+		base.Idempotency.TTL = file.Idempotency.TTL
+	}
+	if file.Idempotency.MaxKeys > 0 {
+		base.Idempotency.MaxKeys = file.Idempotency.MaxKeys
+	}
+
+	// Merge session configuration
+	if file.Sessions.Enabled {
+		base.Sessions.Enabled = true
+	}
+	if file.Sessions.CookieName != "" {
+		base.Sessions.CookieName = file.Sessions.CookieName
+	}
+	if file.Sessions.Login != "" {
+		base.Sessions.Login = file.Sessions.Login
+	}
+	if file.Sessions.Logout != "" {
+		base.Sessions.Logout = file.Sessions.Logout
+	}
+	if len(file.Sessions.Protected) > 0 {
+		base.Sessions.Protected = file.Sessions.Protected
+	}
+	if file.Sessions.TTL > 0 {
+		base.Sessions.TTL = file.Sessions.TTL
+	}
+
+	// Merge cache configuration
+	if file.Cache.Enabled != nil {
+		base.Cache.Enabled = file.Cache.Enabled
+	}
+	if file.Cache.MaxEntries != nil {
+		base.Cache.MaxEntries = file.Cache.MaxEntries
+	}
+	if file.Cache.Warmup {
+		base.Cache.Warmup = true
+	}
+
+	// Merge echo configuration
+	if file.Echo.Enabled {
+		base.Echo.Enabled = true
+	}
+	if len(file.Echo.RedactHeaders) > 0 {
+		base.Echo.RedactHeaders = file.Echo.RedactHeaders
+	}
+
+	// Merge response configuration
+	if file.Response.Pretty {
+		base.Response.Pretty = true
+	}
+	if file.Response.Indent != nil {
+		base.Response.Indent = file.Response.Indent
+	}
+	if file.Response.PreserveOrder {
+		base.Response.PreserveOrder = true
+	}
 
 	// Merge security configuration (including CORS)
 	if file.Security.CORS.Enabled {
@@ -270,9 +543,15 @@ func mergeConfig(base *Config, file *Config) {
 	if file.Security.CORS.AllowCredentials != base.Security.CORS.AllowCredentials {
 		base.Security.CORS.AllowCredentials = file.Security.CORS.AllowCredentials
 	}
-	if file.Security.CORS.MaxAge != base.Security.CORS.MaxAge {
+	if file.Security.CORS.MaxAge != nil {
 		base.Security.CORS.MaxAge = file.Security.CORS.MaxAge
 	}
+	if file.Security.CORS.PreflightStatus != 0 {
+		base.Security.CORS.PreflightStatus = file.Security.CORS.PreflightStatus
+	}
+	if len(file.Security.CORS.Routes) > 0 {
+		base.Security.CORS.Routes = file.Security.CORS.Routes
+	}
 }
 
 // validateFilePath checks if the file path is safe to read
@@ -32,6 +32,11 @@ func boolPtr(b bool) *bool {
 	return &b
 }
 
+// Helper function to create int pointers for CLI flags
+func intPtr(n int) *int {
+	return &n
+}
+
 func TestLoadConfig_ServerPriority(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -190,6 +195,38 @@ security:
 			expectedCredentials: true,
 			expectedMaxAge:      600,
 		},
+		{
+			name: "Omitted max_age keeps the default",
+			configFile: `
+security:
+  cors:
+    allowed_origins: ["https://example.com"]
+`,
+			envVars:             map[string]string{},
+			cliFlags:            nil,
+			expectedCORSEnabled: true,
+			expectedOrigins:     []string{"https://example.com"},
+			expectedMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+			expectedHeaders:     []string{"Content-Type", "Authorization", "Accept"},
+			expectedCredentials: false,
+			expectedMaxAge:      DefaultCORSMaxAge,
+		},
+		{
+			name: "Explicit zero max_age turns off preflight caching",
+			configFile: `
+security:
+  cors:
+    max_age: 0
+`,
+			envVars:             map[string]string{},
+			cliFlags:            nil,
+			expectedCORSEnabled: true,
+			expectedOrigins:     []string{"*"},
+			expectedMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+			expectedHeaders:     []string{"Content-Type", "Authorization", "Accept"},
+			expectedCredentials: false,
+			expectedMaxAge:      0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -250,8 +287,8 @@ security:
 				t.Errorf("Expected credentials %v, got %v", tt.expectedCredentials, config.Security.CORS.AllowCredentials)
 			}
 
-			if config.Security.CORS.MaxAge != tt.expectedMaxAge {
-				t.Errorf("Expected max age %d, got %d", tt.expectedMaxAge, config.Security.CORS.MaxAge)
+			if config.Security.CORS.PreflightMaxAge() != tt.expectedMaxAge {
+				t.Errorf("Expected max age %d, got %d", tt.expectedMaxAge, config.Security.CORS.PreflightMaxAge())
 			}
 		})
 	}
@@ -557,6 +594,7 @@ func TestLoadConfig_EnvironmentVariableParsing(t *testing.T) {
 		expectedSpecFile          string
 		expectedHotReload         bool
 		expectedHotReloadDebounce string
+		expectedShutdownTimeout   string
 		expectedProxyEnabled      bool
 		expectedProxyTarget       string
 		expectedProxyTimeout      string
@@ -578,12 +616,14 @@ func TestLoadConfig_EnvironmentVariableParsing(t *testing.T) {
 				"GO_SPEC_MOCK_TLS_ENABLED":         "true",
 				"GO_SPEC_MOCK_TLS_CERT_FILE":       tlsCertFile,
 				"GO_SPEC_MOCK_TLS_KEY_FILE":        tlsKeyFile,
+				"GO_SPEC_MOCK_SHUTDOWN_TIMEOUT":    "45s",
 			},
 			expectedHost:              "env-host",
 			expectedPort:              "3000",
 			expectedSpecFile:          "/path/to/spec.yaml",
 			expectedHotReload:         true,
 			expectedHotReloadDebounce: "2s",
+			expectedShutdownTimeout:   "45s",
 			expectedProxyEnabled:      true,
 			expectedProxyTarget:       "http://backend.example.com",
 			expectedProxyTimeout:      "30s",
@@ -622,12 +662,14 @@ func TestLoadConfig_EnvironmentVariableParsing(t *testing.T) {
 			envVars: map[string]string{
 				"GO_SPEC_MOCK_HOT_RELOAD_DEBOUNCE": "invalid-duration",
 				"GO_SPEC_MOCK_PROXY_TIMEOUT":       "not-a-duration",
+				"GO_SPEC_MOCK_SHUTDOWN_TIMEOUT":    "soon",
 			},
 			expectedHost:              "localhost", // Default
 			expectedPort:              "8080",      // Default
 			expectedHotReload:         true,        // Default (invalid ignored)
 			expectedHotReloadDebounce: "500ms",     // Default (invalid ignored)
 			expectedProxyTimeout:      "30s",       // Default (invalid ignored)
+			expectedShutdownTimeout:   "30s",       // Default (invalid ignored)
 		},
 		{
 			name: "Empty environment variables should be ignored",
@@ -664,6 +706,12 @@ func TestLoadConfig_EnvironmentVariableParsing(t *testing.T) {
 				t.Errorf("Expected port %q, got %q", tt.expectedPort, config.Server.Port)
 			}
 
+			if tt.expectedShutdownTimeout != "" {
+				if config.Server.ShutdownTimeout.String() != tt.expectedShutdownTimeout {
+					t.Errorf("Expected shutdown timeout %q, got %q", tt.expectedShutdownTimeout, config.Server.ShutdownTimeout.String())
+				}
+			}
+
 			// Verify spec file
 			if config.SpecFile != tt.expectedSpecFile {
 				t.Errorf("Expected spec file %q, got %q", tt.expectedSpecFile, config.SpecFile)
@@ -1084,7 +1132,7 @@ func TestLoadConfig_ConfigurationMerging(t *testing.T) {
 						AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 						AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept"},
 						AllowCredentials: false,
-						MaxAge:           86400,
+						MaxAge:           intPtr(86400),
 					},
 				},
 			},
@@ -1094,7 +1142,7 @@ func TestLoadConfig_ConfigurationMerging(t *testing.T) {
 						AllowedOrigins:   []string{"https://example.com"},
 						AllowedMethods:   []string{"GET", "POST"},
 						AllowCredentials: true,
-						MaxAge:           600,
+						MaxAge:           intPtr(600),
 					},
 				},
 			},
@@ -1106,7 +1154,7 @@ func TestLoadConfig_ConfigurationMerging(t *testing.T) {
 						AllowedMethods:   []string{"GET", "POST"},
 						AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept"}, // Not overridden
 						AllowCredentials: true,
-						MaxAge:           600,
+						MaxAge:           intPtr(600),
 					},
 				},
 			},
@@ -1206,8 +1254,8 @@ func TestLoadConfig_ConfigurationMerging(t *testing.T) {
 			if baseCopy.Security.CORS.AllowCredentials != tt.expectedConfig.Security.CORS.AllowCredentials {
 				t.Errorf("Expected credentials %v, got %v", tt.expectedConfig.Security.CORS.AllowCredentials, baseCopy.Security.CORS.AllowCredentials)
 			}
-			if baseCopy.Security.CORS.MaxAge != tt.expectedConfig.Security.CORS.MaxAge {
-				t.Errorf("Expected max age %d, got %d", tt.expectedConfig.Security.CORS.MaxAge, baseCopy.Security.CORS.MaxAge)
+			if baseCopy.Security.CORS.PreflightMaxAge() != tt.expectedConfig.Security.CORS.PreflightMaxAge() {
+				t.Errorf("Expected max age %d, got %d", tt.expectedConfig.Security.CORS.PreflightMaxAge(), baseCopy.Security.CORS.PreflightMaxAge())
 			}
 
 			// Verify observability configuration
@@ -1223,3 +1271,88 @@ func TestLoadConfig_ConfigurationMerging(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadFromEnv_ServerTimeouts(t *testing.T) {
+	t.Setenv("GO_SPEC_MOCK_READ_TIMEOUT", "20s")
+	t.Setenv("GO_SPEC_MOCK_WRITE_TIMEOUT", "5m")
+	t.Setenv("GO_SPEC_MOCK_IDLE_TIMEOUT", "not-a-duration")
+
+	config, err := LoadConfig("", nil)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.Server.ReadTimeout != 20*time.Second {
+		t.Errorf("Expected read timeout 20s, got %v", config.Server.ReadTimeout)
+	}
+	if config.Server.WriteTimeout != 5*time.Minute {
+		t.Errorf("Expected write timeout 5m, got %v", config.Server.WriteTimeout)
+	}
+	if config.Server.IdleTimeout != 60*time.Second {
+		t.Errorf("Expected invalid idle timeout to keep the 60s default, got %v", config.Server.IdleTimeout)
+	}
+}
+
+func TestLoadConfig_ArrayLengthPriority(t *testing.T) {
+	configFile := "generator:\n  default_array_length: 5\n"
+
+	tests := []struct {
+		name     string
+		file     string
+		env      string
+		cli      *int
+		expected int
+	}{
+		{name: "default", expected: DefaultArrayLength},
+		{name: "file", file: configFile, expected: 5},
+		{name: "env overrides file", file: configFile, env: "8", expected: 8},
+		{name: "cli overrides env", file: configFile, env: "8", cli: intPtr(12), expected: 12},
+		{name: "invalid env ignored", file: configFile, env: "many", expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("GO_SPEC_MOCK_ARRAY_LENGTH", tt.env)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeTempConfig(t, tt.file)
+			}
+			var flags *CLIFlags
+			if tt.cli != nil {
+				flags = &CLIFlags{ArrayLength: tt.cli}
+			}
+
+			config, err := LoadConfig(path, flags)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if config.Generator.DefaultArrayLength != tt.expected {
+				t.Errorf("Expected default array length %d, got %d", tt.expected, config.Generator.DefaultArrayLength)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ArrayLengthMustBePositive(t *testing.T) {
+	t.Setenv("GO_SPEC_MOCK_ARRAY_LENGTH", "0")
+	if _, err := LoadConfig("", nil); err == nil {
+		t.Error("expected error for zero array length from the environment")
+	}
+
+	if _, err := LoadConfig("", &CLIFlags{ArrayLength: intPtr(-3)}); err == nil {
+		t.Error("expected error for negative array length from the CLI")
+	}
+}
+
+func TestLoadFromEnv_AdminToken(t *testing.T) {
+	t.Setenv("GO_SPEC_MOCK_ADMIN_TOKEN", "from-env")
+
+	config, err := LoadConfig(writeTempConfig(t, "admin:\n  enabled: true\n  token: from-file\n"), nil)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.Admin.Token != "from-env" {
+		t.Errorf("Expected the environment to override the admin token, got %q", config.Admin.Token)
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaintenanceConfig contains configuration for simulated maintenance windows
+type MaintenanceConfig struct {
+	Enabled    bool          `json:"enabled" yaml:"enabled"`
+	StatusCode int           `json:"status_code" yaml:"status_code"`
+	RetryAfter time.Duration `json:"retry_after" yaml:"retry_after"`
+	Message    string        `json:"message" yaml:"message"`
+}
+
+// DefaultMaintenanceConfig returns default maintenance configuration
+func DefaultMaintenanceConfig() MaintenanceConfig {
+	return MaintenanceConfig{
+		Enabled:    false,
+		StatusCode: 503,
+		RetryAfter: 60 * time.Second,
+		Message:    "Service is under maintenance",
+	}
+}
+
+// Validate validates the maintenance configuration
+func (m MaintenanceConfig) Validate() error {
+	if m.StatusCode != 0 && (m.StatusCode < 100 || m.StatusCode > 599) {
+		return fmt.Errorf("maintenance status_code must be between 100 and 599")
+	}
+	if m.RetryAfter < 0 {
+		return fmt.Errorf("maintenance retry_after must be non-negative")
+	}
+	return nil
+}
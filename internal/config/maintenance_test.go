@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceConfigValidate_Default(t *testing.T) {
+	cfg := DefaultMaintenanceConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected default maintenance config to be valid, got %v", err)
+	}
+}
+
+func TestMaintenanceConfigValidate_InvalidStatusCode(t *testing.T) {
+	cfg := DefaultMaintenanceConfig()
+	cfg.StatusCode = 700
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for out-of-range status code")
+	}
+}
+
+func TestMaintenanceConfigValidate_NegativeRetryAfter(t *testing.T) {
+	cfg := DefaultMaintenanceConfig()
+	cfg.RetryAfter = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for negative retry_after")
+	}
+}
@@ -2,12 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
 // ObservabilityConfig contains observability-related configuration
 type ObservabilityConfig struct {
 	Logging LoggingConfig `json:"logging" yaml:"logging"`
+	Tracing TracingConfig `json:"tracing" yaml:"tracing"`
 }
 
 // LoggingConfig contains logging configuration
@@ -18,10 +20,27 @@ type LoggingConfig struct {
 	Development bool   `json:"development" yaml:"development"`
 }
 
+// TracingConfig contains OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled     bool   `json:"enabled" yaml:"enabled"`
+	Endpoint    string `json:"endpoint" yaml:"endpoint"`
+	ServiceName string `json:"service_name" yaml:"service_name"`
+}
+
 // DefaultObservabilityConfig returns default observability configuration
 func DefaultObservabilityConfig() ObservabilityConfig {
 	return ObservabilityConfig{
 		Logging: DefaultLoggingConfig(),
+		Tracing: DefaultTracingConfig(),
+	}
+}
+
+// DefaultTracingConfig returns default tracing configuration
+func DefaultTracingConfig() TracingConfig {
+	return TracingConfig{
+		Enabled:     false,
+		Endpoint:    "http://localhost:4318",
+		ServiceName: "go-spec-mock",
 	}
 }
 
@@ -40,6 +59,21 @@ func (o *ObservabilityConfig) Validate() error {
 	if err := o.Logging.Validate(); err != nil {
 		return fmt.Errorf("logging: %w", err)
 	}
+	if err := o.Tracing.Validate(); err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	return nil
+}
+
+// Validate validates the tracing configuration
+func (t *TracingConfig) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	u, err := url.Parse(t.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint must be an http or https URL, got %q", t.Endpoint)
+	}
 	return nil
 }
 
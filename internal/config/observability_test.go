@@ -92,3 +92,24 @@ func TestObservabilityConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestTracingConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TracingConfig
+		wantErr bool
+	}{
+		{name: "disabled ignores endpoint", config: TracingConfig{Enabled: false, Endpoint: ""}, wantErr: false},
+		{name: "default endpoint", config: TracingConfig{Enabled: true, Endpoint: DefaultTracingConfig().Endpoint}, wantErr: false},
+		{name: "missing scheme", config: TracingConfig{Enabled: true, Endpoint: "localhost:4318"}, wantErr: true},
+		{name: "empty endpoint", config: TracingConfig{Enabled: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TracingConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+)
+
+// OutageConfig contains configuration for simulating partial outages
+type OutageConfig struct {
+	StatusCode int      `json:"status_code" yaml:"status_code"`
+	Operations []string `json:"operations" yaml:"operations"`
+}
+
+// DefaultOutageConfig returns default outage configuration
+func DefaultOutageConfig() OutageConfig {
+	return OutageConfig{
+		StatusCode: 503,
+		Operations: []string{},
+	}
+}
+
+// Validate validates the outage configuration
+func (o OutageConfig) Validate() error {
+	if o.StatusCode != 0 && (o.StatusCode < 100 || o.StatusCode > 599) {
+		return fmt.Errorf("outage status_code must be between 100 and 599")
+	}
+	for _, operation := range o.Operations {
+		if operation == "" {
+			return fmt.Errorf("outage operations cannot contain empty entries")
+		}
+	}
+	return nil
+}
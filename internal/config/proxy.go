@@ -2,14 +2,38 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// Proxy modes
+const (
+	ProxyModeLive   = "live"
+	ProxyModeReplay = "replay"
+)
+
 // ProxyConfig contains proxy-specific configuration
 type ProxyConfig struct {
-	Enabled bool          `json:"enabled" yaml:"enabled"`
-	Target  string        `json:"target" yaml:"target"`
-	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	Enabled         bool          `json:"enabled" yaml:"enabled"`
+	Target          string        `json:"target" yaml:"target"`
+	Timeout         time.Duration `json:"timeout" yaml:"timeout"`
+	PathPrefixes    []string      `json:"path_prefixes" yaml:"path_prefixes"`
+	RecordDir       string        `json:"record_dir" yaml:"record_dir"`
+	Mode            string        `json:"mode" yaml:"mode"`
+	ReplayFallback  bool          `json:"replay_fallback" yaml:"replay_fallback"`
+	ReplayMatchBody bool          `json:"replay_match_body" yaml:"replay_match_body"`
+	HealthCheck     bool          `json:"health_check" yaml:"health_check"`
+}
+
+// Replaying reports whether unmatched requests are served from recordings
+func (p ProxyConfig) Replaying() bool {
+	return p.Mode == ProxyModeReplay
+}
+
+// ProbesTarget reports whether readiness should depend on reaching the target.
+// Replay mode without fallback never contacts it, so there is nothing to probe.
+func (p ProxyConfig) ProbesTarget() bool {
+	return p.Enabled && p.HealthCheck && p.Target != "" && (!p.Replaying() || p.ReplayFallback)
 }
 
 // Validate validates the proxy configuration
@@ -18,7 +42,18 @@ func (p ProxyConfig) Validate() error {
 		return nil
 	}
 
-	if p.Target == "" {
+	switch p.Mode {
+	case "", ProxyModeLive:
+	case ProxyModeReplay:
+		if p.RecordDir == "" {
+			return fmt.Errorf("proxy record_dir is required in replay mode")
+		}
+	default:
+		return fmt.Errorf("proxy mode must be %s or %s", ProxyModeLive, ProxyModeReplay)
+	}
+
+	// Replay without fallback never contacts the backend
+	if p.Target == "" && (!p.Replaying() || p.ReplayFallback) {
 		return fmt.Errorf("proxy target cannot be empty when proxy is enabled")
 	}
 
@@ -26,14 +61,40 @@ func (p ProxyConfig) Validate() error {
 		return fmt.Errorf("proxy timeout must be positive")
 	}
 
+	for _, prefix := range p.PathPrefixes {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("proxy path prefix %q must start with /", prefix)
+		}
+	}
+
 	return nil
 }
 
+// Forwards reports whether a request path should be proxied. With no path
+// prefixes configured every path is forwarded. A prefix matches the
+// path itself and anything below it, so /api/legacy matches /api/legacy/users
+// but not /api/legacy-v2.
+func (p ProxyConfig) Forwards(path string) bool {
+	if len(p.PathPrefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range p.PathPrefixes {
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if path == trimmed || strings.HasPrefix(path, trimmed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultProxyConfig returns default proxy configuration
 func DefaultProxyConfig() ProxyConfig {
 	return ProxyConfig{
-		Enabled: false,
-		Target:  "",
-		Timeout: 30 * time.Second,
+		Enabled:      false,
+		Target:       "",
+		Timeout:      30 * time.Second,
+		PathPrefixes: []string{},
+		Mode:         ProxyModeLive,
 	}
 }
@@ -0,0 +1,67 @@
+package config
+
+import "testing"
+
+func TestProxyConfigForwards(t *testing.T) {
+	all := ProxyConfig{}
+	if !all.Forwards("/anything") {
+		t.Error("Expected every path to be forwarded without path prefixes")
+	}
+
+	p := ProxyConfig{PathPrefixes: []string{"/api/legacy", "/v1/"}}
+	tests := map[string]bool{
+		"/api/legacy":       true,
+		"/api/legacy/users": true,
+		"/api/legacy-v2":    false,
+		"/v1":               true,
+		"/v1/orders":        true,
+		"/api/pets":         false,
+	}
+	for path, want := range tests {
+		if got := p.Forwards(path); got != want {
+			t.Errorf("Forwards(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestProxyConfigValidate_PathPrefixes(t *testing.T) {
+	p := DefaultProxyConfig()
+	p.Enabled = true
+	p.Target = "http://localhost:9000"
+
+	p.PathPrefixes = []string{"/api/legacy"}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Expected valid path prefixes, got error: %v", err)
+	}
+
+	p.PathPrefixes = []string{"api/legacy"}
+	if err := p.Validate(); err == nil {
+		t.Fatal("Expected error for path prefix without leading slash")
+	}
+}
+
+func TestProxyConfigValidate_Mode(t *testing.T) {
+	p := DefaultProxyConfig()
+	p.Enabled = true
+	p.Mode = ProxyModeReplay
+	if err := p.Validate(); err == nil {
+		t.Fatal("Expected error for replay mode without record_dir")
+	}
+
+	// Replay without fallback does not need a target
+	p.RecordDir = "./recordings"
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Expected replay without target to be valid, got error: %v", err)
+	}
+
+	p.ReplayFallback = true
+	if err := p.Validate(); err == nil {
+		t.Fatal("Expected error for replay fallback without target")
+	}
+
+	p.Mode = "mirror"
+	p.Target = "http://localhost:9000"
+	if err := p.Validate(); err == nil {
+		t.Fatal("Expected error for unknown proxy mode")
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import "fmt"
+
+// DefaultResponseIndent is the number of spaces per level in pretty-printed responses
+const DefaultResponseIndent = 2
+
+// ResponseConfig contains configuration for how JSON response bodies are written
+type ResponseConfig struct {
+	Pretty bool `json:"pretty" yaml:"pretty"`
+	// Indent is a pointer so that an explicit 0 in a config file is kept
+	Indent *int `json:"indent" yaml:"indent"`
+	// PreserveOrder writes object properties in the order the spec declares them
+	PreserveOrder bool `json:"preserve_order" yaml:"preserve_order"`
+}
+
+// DefaultResponseConfig returns default response configuration
+func DefaultResponseConfig() ResponseConfig {
+	indent := DefaultResponseIndent
+	return ResponseConfig{Indent: &indent}
+}
+
+// IndentWidth returns the configured spaces per level, or DefaultResponseIndent when unset
+func (r ResponseConfig) IndentWidth() int {
+	if r.Indent == nil {
+		return DefaultResponseIndent
+	}
+	return *r.Indent
+}
+
+// Validate validates the response configuration
+func (r ResponseConfig) Validate() error {
+	if n := r.IndentWidth(); n < 0 || n > 8 {
+		return fmt.Errorf("response indent must be between 0 and 8")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestLoadConfig_ResponseIndent(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expected   int
+	}{
+		{name: "default when unset", configFile: "response:\n  pretty: true\n", expected: DefaultResponseIndent},
+		{name: "explicit zero", configFile: "response:\n  pretty: true\n  indent: 0\n", expected: 0},
+		{name: "explicit value", configFile: "response:\n  pretty: true\n  indent: 4\n", expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(writeTempConfig(t, tt.configFile), nil)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if got := config.Response.IndentWidth(); got != tt.expected {
+				t.Errorf("Expected indent %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
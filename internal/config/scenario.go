@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+)
+
+// ScenarioConfig maps values of a request header to named examples
+type ScenarioConfig struct {
+	Header   string            `json:"header" yaml:"header"`
+	Examples map[string]string `json:"examples" yaml:"examples"`
+}
+
+// DefaultScenarioConfig returns default scenario configuration
+func DefaultScenarioConfig() ScenarioConfig {
+	return ScenarioConfig{
+		Header:   "X-Scenario",
+		Examples: map[string]string{},
+	}
+}
+
+// Validate validates the scenario configuration
+func (s ScenarioConfig) Validate() error {
+	if len(s.Examples) > 0 && s.Header == "" {
+		return fmt.Errorf("scenario header cannot be empty when scenario examples are configured")
+	}
+	for value, exampleName := range s.Examples {
+		if exampleName == "" {
+			return fmt.Errorf("scenario %q must map to a named example", value)
+		}
+	}
+	return nil
+}
+
+// ExampleFor returns the named example mapped to the given header value
+func (s ScenarioConfig) ExampleFor(value string) string {
+	if value == "" {
+		return ""
+	}
+	return s.Examples[value]
+}
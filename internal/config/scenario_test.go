@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestScenarioConfigValidate_Default(t *testing.T) {
+	cfg := DefaultScenarioConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected default scenario config to be valid, got %v", err)
+	}
+}
+
+func TestScenarioConfigValidate_FailsWithoutHeader(t *testing.T) {
+	cfg := ScenarioConfig{Examples: map[string]string{"empty": "emptyList"}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error when examples are configured without a header")
+	}
+}
+
+func TestScenarioConfigValidate_FailsWithEmptyExampleName(t *testing.T) {
+	cfg := ScenarioConfig{Header: "X-Scenario", Examples: map[string]string{"empty": ""}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error when a scenario maps to an empty example name")
+	}
+}
+
+func TestScenarioConfigExampleFor(t *testing.T) {
+	cfg := ScenarioConfig{Header: "X-Scenario", Examples: map[string]string{"empty": "emptyList"}}
+
+	if got := cfg.ExampleFor("empty"); got != "emptyList" {
+		t.Errorf("expected emptyList, got %q", got)
+	}
+	if got := cfg.ExampleFor("missing"); got != "" {
+		t.Errorf("expected no example for unmapped value, got %q", got)
+	}
+	if got := cfg.ExampleFor(""); got != "" {
+		t.Errorf("expected no example for empty value, got %q", got)
+	}
+}
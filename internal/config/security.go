@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/leslieo2/go-spec-mock/internal/constants"
 )
@@ -19,9 +21,28 @@ type CORSConfig struct {
 	AllowedMethods   []string `json:"allowed_methods" yaml:"allowed_methods"`
 	AllowedHeaders   []string `json:"allowed_headers" yaml:"allowed_headers"`
 	AllowCredentials bool     `json:"allow_credentials" yaml:"allow_credentials"`
-	MaxAge           int      `json:"max_age" yaml:"max_age"`
+	// MaxAge is a pointer so that an explicit 0 in a config file turns off
+	// preflight caching
+	MaxAge          *int `json:"max_age" yaml:"max_age"`
+	PreflightStatus int  `json:"preflight_status" yaml:"preflight_status"`
+	// Routes overrides the settings above for individual OpenAPI paths, e.g. "/pets/{id}"
+	Routes map[string]CORSRouteConfig `json:"routes" yaml:"routes"`
 }
 
+// CORSRouteConfig overrides the global CORS settings for one path. Empty fields
+// inherit the global value.
+type CORSRouteConfig struct {
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
+	AllowedMethods []string `json:"allowed_methods" yaml:"allowed_methods"`
+	AllowedHeaders []string `json:"allowed_headers" yaml:"allowed_headers"`
+	// AllowCredentials is a pointer so that an explicit false overrides a global true
+	AllowCredentials *bool `json:"allow_credentials" yaml:"allow_credentials"`
+	MaxAge           int   `json:"max_age" yaml:"max_age"`
+}
+
+// DefaultCORSMaxAge is how long browsers may cache preflight results, in seconds
+const DefaultCORSMaxAge = 86400 // 24 hours
+
 // DefaultSecurityConfig returns default security configuration
 func DefaultSecurityConfig() SecurityConfig {
 	return SecurityConfig{
@@ -31,13 +52,16 @@ func DefaultSecurityConfig() SecurityConfig {
 
 // DefaultCORSConfig returns default CORS configuration
 func DefaultCORSConfig() CORSConfig {
+	maxAge := DefaultCORSMaxAge
 	return CORSConfig{
 		Enabled:          true,
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{constants.MethodGET, constants.MethodPOST, constants.MethodPUT, constants.MethodDELETE, constants.MethodOPTIONS, constants.MethodPATCH},
 		AllowedHeaders:   []string{constants.HeaderContentType, constants.HeaderAuthorization, constants.HeaderAccept},
 		AllowCredentials: false,
-		MaxAge:           86400, // 24 hours
+		MaxAge:           &maxAge,
+		PreflightStatus:  http.StatusNoContent,
+		Routes:           map[string]CORSRouteConfig{},
 	}
 }
 
@@ -55,6 +79,14 @@ func (s *SecurityConfig) Validate() error {
 	return nil
 }
 
+// PreflightMaxAge returns the Access-Control-Max-Age in seconds, 0 to send none
+func (c CORSConfig) PreflightMaxAge() int {
+	if c.MaxAge == nil {
+		return DefaultCORSMaxAge
+	}
+	return *c.MaxAge
+}
+
 // Validate validates the CORS configuration
 func (c *CORSConfig) Validate() error {
 	if c.Enabled {
@@ -65,5 +97,45 @@ func (c *CORSConfig) Validate() error {
 			return fmt.Errorf("allowed_methods must not be empty")
 		}
 	}
+	if c.PreflightMaxAge() < 0 {
+		return fmt.Errorf("max_age must be non-negative")
+	}
+	if c.PreflightStatus != 0 && c.PreflightStatus != http.StatusOK && c.PreflightStatus != http.StatusNoContent {
+		return fmt.Errorf("preflight_status must be %d or %d", http.StatusOK, http.StatusNoContent)
+	}
+	for path, route := range c.Routes {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("routes path %q must start with /", path)
+		}
+		if route.MaxAge < 0 {
+			return fmt.Errorf("routes max_age for %s must be non-negative", path)
+		}
+	}
 	return nil
 }
+
+// ForRoute returns the CORS settings for an OpenAPI path, with its entry in
+// Routes applied over the global settings
+func (c CORSConfig) ForRoute(path string) CORSConfig {
+	route, ok := c.Routes[path]
+	if !ok {
+		return c
+	}
+	if len(route.AllowedOrigins) > 0 {
+		c.AllowedOrigins = route.AllowedOrigins
+	}
+	if len(route.AllowedMethods) > 0 {
+		c.AllowedMethods = route.AllowedMethods
+	}
+	if len(route.AllowedHeaders) > 0 {
+		c.AllowedHeaders = route.AllowedHeaders
+	}
+	if route.AllowCredentials != nil {
+		c.AllowCredentials = *route.AllowCredentials
+	}
+	if route.MaxAge > 0 {
+		c.MaxAge = &route.MaxAge
+	}
+	c.Routes = nil
+	return c
+}
@@ -52,10 +52,60 @@ func TestCORSConfigValidate_PassesWithExplicitValues(t *testing.T) {
 			AllowedMethods:   []string{"GET", "POST"},
 			AllowedHeaders:   []string{"Content-Type"},
 			AllowCredentials: true,
-			MaxAge:           600,
+			MaxAge:           intPtr(600),
 		},
 	}
 	if err := s.Validate(); err != nil {
 		t.Fatalf("Expected valid configuration, got error: %v", err)
 	}
 }
+
+func TestCORSConfigValidate_PreflightStatus(t *testing.T) {
+	for _, status := range []int{0, 200, 204} {
+		c := CORSConfig{PreflightStatus: status}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected preflight_status %d to be valid, got error: %v", status, err)
+		}
+	}
+
+	c := CORSConfig{PreflightStatus: 302}
+	if err := c.Validate(); err == nil {
+		t.Fatal("Expected error for preflight_status 302")
+	}
+}
+
+func TestCORSConfigForRoute(t *testing.T) {
+	allow := true
+	c := DefaultCORSConfig()
+	c.AllowedOrigins = []string{"https://app.example.com"}
+	c.Routes = map[string]CORSRouteConfig{
+		"/public": {AllowedOrigins: []string{"*"}, AllowCredentials: &allow},
+	}
+
+	route := c.ForRoute("/public")
+	if len(route.AllowedOrigins) != 1 || route.AllowedOrigins[0] != "*" {
+		t.Errorf("Expected route origins to override the global ones, got %v", route.AllowedOrigins)
+	}
+	if !route.AllowCredentials {
+		t.Error("Expected route allow_credentials to override the global value")
+	}
+	if route.PreflightMaxAge() != c.PreflightMaxAge() || len(route.AllowedMethods) != len(c.AllowedMethods) {
+		t.Error("Expected unset route fields to inherit the global values")
+	}
+
+	if other := c.ForRoute("/private"); other.AllowedOrigins[0] != "https://app.example.com" {
+		t.Errorf("Expected paths without an override to keep the global origins, got %v", other.AllowedOrigins)
+	}
+}
+
+func TestCORSConfigValidate_Routes(t *testing.T) {
+	c := CORSConfig{Routes: map[string]CORSRouteConfig{"public": {}}}
+	if err := c.Validate(); err == nil {
+		t.Error("Expected error for a route path without a leading /")
+	}
+
+	c = CORSConfig{Routes: map[string]CORSRouteConfig{"/public": {MaxAge: -1}}}
+	if err := c.Validate(); err == nil {
+		t.Error("Expected error for a negative route max_age")
+	}
+}
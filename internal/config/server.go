@@ -3,12 +3,69 @@ package config
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// Example rotation strategies for responses with several named examples
+const (
+	ExampleRotationFirst      = "first"
+	ExampleRotationRoundRobin = "roundrobin"
+	ExampleRotationRandom     = "random"
 )
 
+// DefaultMaxQueryLength is the default limit on raw query string length in bytes
+const DefaultMaxQueryLength = 8192
+
 // ServerConfig contains server-specific configuration
 type ServerConfig struct {
-	Host string `json:"host" yaml:"host"`
-	Port string `json:"port" yaml:"port"`
+	Host                  string           `json:"host" yaml:"host"`
+	Port                  string           `json:"port" yaml:"port"`
+	ExampleRotation       string           `json:"example_rotation" yaml:"example_rotation"`
+	WarmupDelay           time.Duration    `json:"warmup_delay" yaml:"warmup_delay"`
+	ResponseDelay         time.Duration    `json:"response_delay" yaml:"response_delay"`
+	MaxQueryLength        *int             `json:"max_query_length" yaml:"max_query_length"`
+	RouteMaxRequestSize   map[string]int64 `json:"route_max_request_size" yaml:"route_max_request_size"`
+	HALLinks              bool             `json:"hal_links" yaml:"hal_links"`
+	CacheDebug            bool             `json:"cache_debug" yaml:"cache_debug"`
+	Collapse2xx           bool             `json:"collapse_2xx" yaml:"collapse_2xx"`
+	ErrorFormat           string           `json:"error_format" yaml:"error_format"`
+	RawSpecPath           string           `json:"raw_spec_path" yaml:"raw_spec_path"`
+	Compression           bool             `json:"compression" yaml:"compression"`
+	CompressionLevel      int              `json:"compression_level" yaml:"compression_level"`
+	MethodOverride        bool             `json:"method_override" yaml:"method_override"`
+	AutoHead              bool             `json:"auto_head" yaml:"auto_head"`
+	ShutdownTimeout       time.Duration    `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	ReadTimeout           time.Duration    `json:"read_timeout" yaml:"read_timeout"`
+	WriteTimeout          time.Duration    `json:"write_timeout" yaml:"write_timeout"`
+	IdleTimeout           time.Duration    `json:"idle_timeout" yaml:"idle_timeout"`
+	DisableKeepAlives     bool             `json:"disable_keep_alives" yaml:"disable_keep_alives"`
+	GenerationTimeout     time.Duration    `json:"generation_timeout" yaml:"generation_timeout"`
+	AllowResponseOverride bool             `json:"allow_response_override" yaml:"allow_response_override"`
+	UseServerBasePath     bool             `json:"use_server_base_path" yaml:"use_server_base_path"`
+	StrictSlash           *bool            `json:"strict_slash" yaml:"strict_slash"`
+	CaseInsensitivePaths  bool             `json:"case_insensitive_paths" yaml:"case_insensitive_paths"`
+	StripPathPrefix       string           `json:"strip_path_prefix" yaml:"strip_path_prefix"`
+	AddPathPrefix         string           `json:"add_path_prefix" yaml:"add_path_prefix"`
+}
+
+// IsStrictSlash reports whether paths with a trailing slash are distinct from
+// paths without one. StrictSlash is a pointer so that an explicit false in a
+// config file relaxes matching.
+func (s ServerConfig) IsStrictSlash() bool {
+	return s.StrictSlash == nil || *s.StrictSlash
+}
+
+// QueryLengthLimit returns the maximum raw query string length in bytes, 0 for
+// no limit. MaxQueryLength is a pointer so that an explicit 0 in a config file
+// disables the limit.
+func (s ServerConfig) QueryLengthLimit() int {
+	if s.MaxQueryLength == nil {
+		return DefaultMaxQueryLength
+	}
+	return *s.MaxQueryLength
 }
 
 // Validate validates the server configuration
@@ -21,6 +78,72 @@ func (s *ServerConfig) Validate() error {
 		return err
 	}
 
+	if s.WarmupDelay < 0 {
+		return fmt.Errorf("warmup_delay must be non-negative")
+	}
+
+	if s.ResponseDelay < 0 {
+		return fmt.Errorf("response_delay must be non-negative")
+	}
+
+	if s.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown_timeout must be non-negative")
+	}
+
+	if s.ReadTimeout < 0 {
+		return fmt.Errorf("read_timeout must be non-negative")
+	}
+
+	if s.WriteTimeout < 0 {
+		return fmt.Errorf("write_timeout must be non-negative")
+	}
+
+	if s.IdleTimeout < 0 {
+		return fmt.Errorf("idle_timeout must be non-negative")
+	}
+
+	if s.GenerationTimeout < 0 {
+		return fmt.Errorf("generation_timeout must be non-negative")
+	}
+
+	if s.QueryLengthLimit() < 0 {
+		return fmt.Errorf("max_query_length must be non-negative")
+	}
+
+	for operation, size := range s.RouteMaxRequestSize {
+		if strings.TrimSpace(operation) == "" {
+			return fmt.Errorf("route_max_request_size cannot contain empty operations")
+		}
+		if size <= 0 {
+			return fmt.Errorf("route_max_request_size for %s must be positive", operation)
+		}
+	}
+
+	if s.CompressionLevel < 0 || s.CompressionLevel > 9 {
+		return fmt.Errorf("compression_level must be between 1 and 9, or 0 for the default")
+	}
+
+	if s.RawSpecPath != "" && !strings.HasPrefix(s.RawSpecPath, "/") {
+		return fmt.Errorf("raw_spec_path must start with /")
+	}
+
+	for name, prefix := range map[string]string{"strip_path_prefix": s.StripPathPrefix, "add_path_prefix": s.AddPathPrefix} {
+		if prefix != "" && (!strings.HasPrefix(prefix, "/") || strings.Trim(prefix, "/") == "") {
+			return fmt.Errorf("%s must start with / and contain at least one path segment", name)
+		}
+	}
+
+	if s.ErrorFormat != "" && !validateErrorFormat(s.ErrorFormat) {
+		return fmt.Errorf("error_format must be %s or render valid JSON", ErrorFormatProblemJSON)
+	}
+
+	switch s.ExampleRotation {
+	case "", ExampleRotationFirst, ExampleRotationRoundRobin, ExampleRotationRandom:
+	default:
+		return fmt.Errorf("example_rotation must be one of %s, %s, %s",
+			ExampleRotationFirst, ExampleRotationRoundRobin, ExampleRotationRandom)
+	}
+
 	return nil
 }
 
@@ -49,8 +172,19 @@ func validatePort(portStr, fieldName string) error {
 
 // DefaultServerConfig returns default server configuration
 func DefaultServerConfig() ServerConfig {
+	strictSlash := true
+	maxQueryLength := DefaultMaxQueryLength
 	return ServerConfig{
-		Host: "localhost",
-		Port: "8080",
+		Host:            "localhost",
+		Port:            "8080",
+		ExampleRotation: ExampleRotationFirst,
+		MaxQueryLength:  &maxQueryLength,
+		ShutdownTimeout: constants.ServerShutdownTimeout,
+		ReadTimeout:     constants.ServerReadTimeout,
+		WriteTimeout:    constants.ServerWriteTimeout,
+		IdleTimeout:     constants.ServerIdleTimeout,
+		StrictSlash:     &strictSlash,
+
+		RouteMaxRequestSize: map[string]int64{},
 	}
 }
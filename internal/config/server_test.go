@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 func TestDefaultServerConfig(t *testing.T) {
@@ -13,6 +14,9 @@ func TestDefaultServerConfig(t *testing.T) {
 	if cfg.Port != "8080" {
 		t.Errorf("DefaultServerConfig Port got %s, want 8080", cfg.Port)
 	}
+	if cfg.QueryLengthLimit() != DefaultMaxQueryLength {
+		t.Errorf("DefaultServerConfig MaxQueryLength got %d, want %d", cfg.QueryLengthLimit(), DefaultMaxQueryLength)
+	}
 
 }
 
@@ -43,6 +47,107 @@ func TestServerConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Round-robin Example Rotation",
+			config: ServerConfig{
+				Host:            "localhost",
+				Port:            "8080",
+				ExampleRotation: ExampleRotationRoundRobin,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Path Prefixes",
+			config: ServerConfig{
+				Host:            "localhost",
+				Port:            "8080",
+				StripPathPrefix: "/mock",
+				AddPathPrefix:   "/api/v1/",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Relative Strip Path Prefix",
+			config: ServerConfig{
+				Host:            "localhost",
+				Port:            "8080",
+				StripPathPrefix: "mock",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Root Add Path Prefix",
+			config: ServerConfig{
+				Host:          "localhost",
+				Port:          "8080",
+				AddPathPrefix: "/",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown Example Rotation",
+			config: ServerConfig{
+				Host:            "localhost",
+				Port:            "8080",
+				ExampleRotation: "shuffle",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Warmup Delay",
+			config: ServerConfig{
+				Host:        "localhost",
+				Port:        "8080",
+				WarmupDelay: -time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Compression Level In Range",
+			config: ServerConfig{
+				Host:             "localhost",
+				Port:             "8080",
+				Compression:      true,
+				CompressionLevel: 9,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Compression Level Out Of Range",
+			config: ServerConfig{
+				Host:             "localhost",
+				Port:             "8080",
+				CompressionLevel: 10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid Error Format",
+			config: ServerConfig{
+				Host:        "localhost",
+				Port:        "8080",
+				ErrorFormat: `{"code": {{status}}, "detail": "{{message}}", "allowed": "{{methods}}"}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Problem JSON Error Format",
+			config: ServerConfig{
+				Host:        "localhost",
+				Port:        "8080",
+				ErrorFormat: ErrorFormatProblemJSON,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Error Format Rendering Invalid JSON",
+			config: ServerConfig{
+				Host:        "localhost",
+				Port:        "8080",
+				ErrorFormat: `{"detail": {{message}}}`,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -55,6 +160,30 @@ func TestServerConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_MaxQueryLength(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expected   int
+	}{
+		{name: "default when unset", configFile: "server:\n  port: \"8080\"\n", expected: DefaultMaxQueryLength},
+		{name: "explicit zero disables the limit", configFile: "server:\n  max_query_length: 0\n", expected: 0},
+		{name: "explicit value", configFile: "server:\n  max_query_length: 1024\n", expected: 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(writeTempConfig(t, tt.configFile), nil)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if got := config.Server.QueryLengthLimit(); got != tt.expected {
+				t.Errorf("Expected max_query_length %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
 func Test_validatePort(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -103,3 +232,12 @@ func Test_validatePort(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderErrorFormat(t *testing.T) {
+	format := `{"code": {{status}}, "detail": "{{message}}", "allowed": "{{methods}}"}`
+	got := RenderErrorFormat(format, 405, `Method "PATCH" not allowed`, []string{"GET", "POST"})
+	want := `{"code": 405, "detail": "Method \"PATCH\" not allowed", "allowed": "GET, POST"}`
+	if got != want {
+		t.Errorf("RenderErrorFormat() = %s, want %s", got, want)
+	}
+}
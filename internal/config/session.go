@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// SessionConfig contains configuration for cookie-based session simulation.
+// Operations are named by operationId or "METHOD /path".
+type SessionConfig struct {
+	Enabled    bool          `json:"enabled" yaml:"enabled"`
+	CookieName string        `json:"cookie_name" yaml:"cookie_name"`
+	Login      string        `json:"login" yaml:"login"`
+	Logout     string        `json:"logout" yaml:"logout"`
+	Protected  []string      `json:"protected" yaml:"protected"`
+	TTL        time.Duration `json:"ttl" yaml:"ttl"`
+}
+
+// DefaultSessionConfig returns default session configuration
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		Enabled:    false,
+		CookieName: "session_id",
+		Protected:  []string{},
+		TTL:        time.Hour,
+	}
+}
+
+// Validate validates the session configuration
+func (s SessionConfig) Validate() error {
+	if s.TTL < 0 {
+		return fmt.Errorf("session ttl must be non-negative")
+	}
+	for _, operation := range s.Protected {
+		if operation == "" {
+			return fmt.Errorf("session protected operations cannot contain empty entries")
+		}
+	}
+	if !s.Enabled {
+		return nil
+	}
+
+	if s.CookieName == "" {
+		return fmt.Errorf("session cookie_name cannot be empty when sessions are enabled")
+	}
+	if s.Login == "" {
+		return fmt.Errorf("session login operation is required when sessions are enabled")
+	}
+	if s.TTL == 0 {
+		return fmt.Errorf("session ttl must be positive when sessions are enabled")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestSessionConfigValidate(t *testing.T) {
+	if err := (SessionConfig{}).Validate(); err != nil {
+		t.Fatalf("expected zero-value config to be valid, got %v", err)
+	}
+
+	cfg := DefaultSessionConfig()
+	cfg.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error when sessions are enabled without a login operation")
+	}
+
+	cfg.Login = "POST /login"
+	cfg.Protected = []string{"getProfile"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid session config, got %v", err)
+	}
+
+	cfg.CookieName = ""
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for empty cookie_name")
+	}
+
+	cfg = DefaultSessionConfig()
+	cfg.Protected = []string{""}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for empty protected entry")
+	}
+}
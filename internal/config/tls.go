@@ -23,7 +23,7 @@ func DefaultTLSConfig() TLSConfig {
 
 // Validate validates the TLS configuration
 func (c TLSConfig) Validate() error {
-	if !c.Enabled {
+	if !c.Enabled || c.SelfSigned() {
 		return nil
 	}
 	if c.CertFile == "" {
@@ -40,3 +40,9 @@ func (c TLSConfig) Validate() error {
 	}
 	return nil
 }
+
+// SelfSigned reports whether TLS should use a generated self-signed certificate
+// because neither a certificate nor a key file is configured
+func (c TLSConfig) SelfSigned() bool {
+	return c.CertFile == "" && c.KeyFile == ""
+}
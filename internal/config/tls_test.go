@@ -13,10 +13,13 @@ func TestTLSConfigValidate_Disabled(t *testing.T) {
 	}
 }
 
-func TestTLSConfigValidate_EnabledMissingCert(t *testing.T) {
+func TestTLSConfigValidate_EnabledSelfSigned(t *testing.T) {
 	cfg := TLSConfig{Enabled: true, CertFile: "", KeyFile: ""}
-	if err := cfg.Validate(); err == nil {
-		t.Fatal("expected error when TLS enabled without certificate paths")
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected TLS without certificate paths to use a self-signed certificate, got %v", err)
+	}
+	if !cfg.SelfSigned() {
+		t.Fatal("expected SelfSigned to report true without certificate paths")
 	}
 }
 
@@ -15,6 +15,12 @@ const (
 	EnvTLSEnabled        = "GO_SPEC_MOCK_TLS_ENABLED"
 	EnvTLSCertFile       = "GO_SPEC_MOCK_TLS_CERT_FILE"
 	EnvTLSKeyFile        = "GO_SPEC_MOCK_TLS_KEY_FILE"
+	EnvShutdownTimeout   = "GO_SPEC_MOCK_SHUTDOWN_TIMEOUT"
+	EnvReadTimeout       = "GO_SPEC_MOCK_READ_TIMEOUT"
+	EnvWriteTimeout      = "GO_SPEC_MOCK_WRITE_TIMEOUT"
+	EnvIdleTimeout       = "GO_SPEC_MOCK_IDLE_TIMEOUT"
+	EnvArrayLength       = "GO_SPEC_MOCK_ARRAY_LENGTH"
+	EnvAdminToken        = "GO_SPEC_MOCK_ADMIN_TOKEN"
 )
 
 // HTTP method constants
@@ -41,17 +47,42 @@ const (
 	StatusServiceUnavailable  = 503
 )
 
+// SpecFileStdin as the spec file reads the specification from standard input
+const SpecFileStdin = "-"
+
 // HTTP header constants
 const (
-	HeaderAuthorization = "Authorization"
-	HeaderContentType   = "Content-Type"
-	HeaderAccept        = "Accept"
-	HeaderOrigin        = "Origin"
+	HeaderAuthorization   = "Authorization"
+	HeaderContentType     = "Content-Type"
+	HeaderAccept          = "Accept"
+	HeaderOrigin          = "Origin"
+	HeaderAcceptVersion   = "Accept-Version"
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderRetryAfter      = "Retry-After"
+	HeaderAllow           = "Allow"
+	HeaderVary            = "Vary"
+	HeaderAcceptEncoding  = "Accept-Encoding"
+	HeaderContentEncoding = "Content-Encoding"
+	HeaderMethodOverride  = "X-HTTP-Method-Override"
+	HeaderTrailer         = "Trailer"
+	HeaderRequestID       = "X-Request-Id"
+	HeaderMockCache       = "X-Mock-Cache"
+	HeaderMockCacheKey    = "X-Mock-Cache-Key"
+	HeaderMockResponse    = "X-Mock-Response"
+	HeaderPrefer          = "Prefer"
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
 )
 
 // Content type constants
 const (
-	ContentTypeJSON = "application/json"
+	ContentTypeJSON              = "application/json"
+	ContentTypeProblemJSON       = "application/problem+json"
+	ContentTypeMultipartFormData = "multipart/form-data"
+	ContentTypeYAML              = "application/yaml"
+	ContentTypeHTML              = "text/html; charset=utf-8"
 )
 
 // CORS headers
@@ -61,6 +92,7 @@ const (
 	HeaderAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
 	HeaderAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
 	HeaderAccessControlMaxAge           = "Access-Control-Max-Age"
+	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
 )
 
 // Server timeout constants (internal use only - not user configurable)
@@ -73,8 +105,12 @@ const (
 	ServerIdleTimeout = 60 * time.Second
 	// ServerMaxRequestSize is the maximum request body size (10MB)
 	ServerMaxRequestSize = 10 * 1024 * 1024
-	// ServerShutdownTimeout is the graceful shutdown timeout
+	// ServerShutdownTimeout is the default graceful shutdown timeout, used for SIGTERM
 	ServerShutdownTimeout = 30 * time.Second
+	// ServerInterruptShutdownTimeout is the shorter drain used for SIGINT (Ctrl-C)
+	ServerInterruptShutdownTimeout = 2 * time.Second
+	// ProxyHealthCheckTimeout caps how long /ready waits for the proxy target
+	ProxyHealthCheckTimeout = 2 * time.Second
 )
 
 // Path constants for skipped authentication
@@ -82,13 +118,43 @@ const (
 	PathHealth        = "/health"
 	PathReady         = "/ready"
 	PathDocumentation = "/docs"
+	PathOpenAPIJSON   = "/openapi.json"
+	PathOpenAPIYAML   = "/openapi.yaml"
+)
+
+// Admin endpoint paths
+const (
+	PathAdminMaintenance = "/admin/maintenance"
+	PathAdminOutages     = "/admin/outages"
+	PathAdminStats       = "/admin/stats"
+	PathAdminReload      = "/admin/reload"
 )
 
 // Query parameter constants
 const (
-	QueryParamStatusCode = "__statusCode"
-	QueryParamDelay      = "__delay"
-	QueryParamExample    = "__example"
+	QueryParamStatusCode  = "__statusCode"
+	QueryParamDelay       = "__delay"
+	QueryParamDelayStatus = "__delayStatus"
+	QueryParamExample     = "__example"
+	QueryParamNoCache     = "__noCache"
+	QueryParamEcho        = "__echo"
+)
+
+// OpenAPI extension constants
+const (
+	ExtensionMockVersions      = "x-mock-versions"
+	ExtensionMockTrailers      = "x-mock-trailers"
+	ExtensionMockCache         = "x-mock-cache"
+	ExtensionMockUniqueBy      = "x-mock-unique-by"
+	ExtensionMockCount         = "x-mock-count"
+	ExtensionMockDelay         = "x-mock-delay"
+	ExtensionMockQueryExamples = "x-mock-query-examples"
+	ExtensionMockWeights       = "x-mock-weights"
+	ExtensionMockUnimplemented = "x-mock-unimplemented"
+	ExtensionMockRotation      = "x-mock-rotation"
+
+	// TrailerValueSHA256 in x-mock-trailers is replaced by the body's SHA-256 digest
+	TrailerValueSHA256 = "$sha256"
 )
 
 // Context key type for avoiding collisions
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// isAddressObject reports whether an object schema describes a postal address,
+// judged by its property name (e.g. "address", "shippingAddress") or title
+func isAddressObject(schema *openapi3.Schema, ctx GenerationContext) bool {
+	return strings.Contains(strings.ToLower(ctx.FieldName), "address") ||
+		strings.Contains(strings.ToLower(schema.Title), "address")
+}
+
+// addressComponent returns the value of the address component matching a property
+// name, so that all sub-fields of an address object describe the same place
+func addressComponent(address Address, propName string, prop *openapi3.Schema) (interface{}, bool) {
+	// Explicit examples, enums, and formats keep priority over address data
+	if prop.Example != nil || len(prop.Enum) > 0 || prop.Format != "" {
+		return nil, false
+	}
+
+	lowerName := strings.ToLower(propName)
+
+	if primaryType(prop.Type) == "number" {
+		switch {
+		case strings.Contains(lowerName, "lat"):
+			return address.Latitude, true
+		case strings.Contains(lowerName, "lng") || strings.Contains(lowerName, "lon"):
+			return address.Longitude, true
+		}
+		return nil, false
+	}
+
+	if primaryType(prop.Type) != "string" {
+		return nil, false
+	}
+
+	switch {
+	case strings.Contains(lowerName, "street") || strings.Contains(lowerName, "line1") ||
+		strings.Contains(lowerName, "line_1") || strings.Contains(lowerName, "address"):
+		return address.Street, true
+	case strings.Contains(lowerName, "city") || strings.Contains(lowerName, "town"):
+		return address.City, true
+	case strings.Contains(lowerName, "state") || strings.Contains(lowerName, "province") ||
+		strings.Contains(lowerName, "region"):
+		return address.State, true
+	case strings.Contains(lowerName, "zip") || strings.Contains(lowerName, "postal") ||
+		strings.Contains(lowerName, "postcode"):
+		return address.PostalCode, true
+	case strings.Contains(lowerName, "countrycode") || strings.Contains(lowerName, "country_code"):
+		return address.CountryCode, true
+	case strings.Contains(lowerName, "country"):
+		return address.Country, true
+	}
+
+	return nil, false
+}
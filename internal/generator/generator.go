@@ -1,23 +1,56 @@
 package generator
 
 import (
+	"crypto/sha1" // #nosec G505 - used for identifiers, not security
 	"fmt"
 	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"go.uber.org/zap"
 )
 
+// DefaultMaxArrayLength bounds generated arrays when no explicit cap is configured
+const DefaultMaxArrayLength = 1000
+
+// DefaultMapEntries is how many synthetic keys open map schemas get by default
+const DefaultMapEntries = 2
+
+// MaxGenerationDepth bounds nesting so self-referential schemas always terminate
+const MaxGenerationDepth = 20
+
 // Config holds configuration options for the data generator
 type Config struct {
-	UseFieldNameForData bool // Infer data from field names
-	DefaultArrayLength  int  // Default array size
+	UseFieldNameForData    bool        // Infer data from field names
+	DefaultArrayLength     int         // Default array size
+	MaxArrayLength         int         // Upper bound on generated array size
+	MapEntries             *int        // Synthetic keys from additionalProperties; nil means DefaultMapEntries
+	StableUUIDs            bool        // Derive UUIDs from path parameters and field names
+	Seed                   int64       // Repeat the same data for the same seed; 0 is random
+	NullProbability        float64     // Chance of generating null for nullable schemas
+	BooleanTrueProbability *float64    // Chance of generating true; nil means a fair coin
+	Logger                 *zap.Logger // Logger for generation warnings
 }
 
 // GenerationContext provides context for data generation
 type GenerationContext struct {
 	FieldName     string   // Current property name for context-aware generation
 	ParentSchemas []string // Track schemas to prevent infinite recursion
+	Depth         int      // Nesting level of the current schema
+
+	// PathParams holds request path parameter values by name; properties of the
+	// top-level object with a matching name take the value from the path
+	PathParams map[string]string
+
+	// Seed identifies the current value within a resource when StableUUIDs is
+	// set, so that UUIDs are the same on every request for the same path
+	// parameters; empty generates random UUIDs
+	Seed string
 }
 
 // Generator handles dynamic data generation from OpenAPI schemas
@@ -32,12 +65,26 @@ func New(config Config) *Generator {
 	if config.DefaultArrayLength == 0 {
 		config.DefaultArrayLength = 2 // Default to 2 items in arrays
 	}
+	if config.MaxArrayLength <= 0 {
+		config.MaxArrayLength = DefaultMaxArrayLength
+	}
+	if config.MapEntries == nil {
+		mapEntries := DefaultMapEntries
+		config.MapEntries = &mapEntries
+	}
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
 
 	g := &Generator{
 		config: config,
 	}
 
-	g.randomSource = NewSecureRandomSource()
+	if config.Seed != 0 {
+		g.randomSource = NewSeededRandomSource(config.Seed)
+	} else {
+		g.randomSource = NewSecureRandomSource()
+	}
 
 	g.initFormatHandlers()
 	return g
@@ -73,6 +120,15 @@ func (g *Generator) GenerateDataWithContext(schema *openapi3.Schema, ctx Generat
 		return nil
 	}
 
+	// Hard stop for recursive schemas, including ones without a title
+	if ctx.Depth > MaxGenerationDepth {
+		return nil
+	}
+
+	if g.config.StableUUIDs && ctx.Seed == "" && len(ctx.PathParams) > 0 {
+		ctx.Seed = pathParamSeed(ctx.PathParams)
+	}
+
 	// Check for circular reference prevention
 	if schema.Title != "" {
 		for _, parent := range ctx.ParentSchemas {
@@ -87,16 +143,26 @@ func (g *Generator) GenerateDataWithContext(schema *openapi3.Schema, ctx Generat
 		return schema.Example
 	}
 
+	// Nullable schemas occasionally generate null to exercise client null handling
+	if isNullable(schema) && g.config.NullProbability > 0 && g.randFloat64() < g.config.NullProbability {
+		return nil
+	}
+
 	// Priority 2: Enum values
 	if len(schema.Enum) > 0 {
-		return schema.Enum[0] // For now, take first enum value (can be randomized later)
+		return g.selectEnum(schema, ctx)
 	}
 
-	// Priority 3: Schema composition support
+	// Priority 3: Schema composition support. Each level counts toward the
+	// depth, so schemas referring to themselves only through allOf, oneOf, or
+	// anyOf still terminate.
+	composedCtx := ctx
+	composedCtx.Depth++
+
 	if len(schema.AllOf) > 0 {
-		mergedSchema := g.mergeSchemas(schema.AllOf)
+		mergedSchema := g.mergeSchemas(schema.AllOf, ctx)
 		if mergedSchema != nil {
-			return g.GenerateDataWithContext(mergedSchema, ctx)
+			return g.GenerateDataWithContext(mergedSchema, composedCtx)
 		}
 	}
 
@@ -104,36 +170,53 @@ func (g *Generator) GenerateDataWithContext(schema *openapi3.Schema, ctx Generat
 		// In deterministic mode, always pick the first schema
 		selectedSchema := schema.OneOf[g.randIntn(len(schema.OneOf))]
 		if selectedSchema.Value != nil {
-			return g.GenerateDataWithContext(selectedSchema.Value, ctx)
+			return g.GenerateDataWithContext(selectedSchema.Value, composedCtx)
 		}
 	}
 
 	if len(schema.AnyOf) > 0 {
 		selectedSchema := schema.AnyOf[g.randIntn(len(schema.AnyOf))]
 		if selectedSchema.Value != nil {
-			return g.GenerateDataWithContext(selectedSchema.Value, ctx)
+			return g.GenerateDataWithContext(selectedSchema.Value, composedCtx)
 		}
 	}
 
 	// Priority 4-7: Type-specific generation
-	switch {
-	case schema.Type.Is("object"):
+	switch primaryType(schema.Type) {
+	case "object":
 		return g.generateObject(schema, ctx)
-	case schema.Type.Is("array"):
+	case "array":
 		return g.generateArray(schema, ctx)
-	case schema.Type.Is("string"):
+	case "string":
 		return g.generateString(schema, ctx)
-	case schema.Type.Is("number"):
+	case "number":
 		return g.generateNumber(schema, ctx)
-	case schema.Type.Is("integer"):
+	case "integer":
 		return g.generateInteger(schema, ctx)
-	case schema.Type.Is("boolean"):
+	case "boolean":
 		return g.generateBoolean(schema, ctx)
 	default:
 		return nil
 	}
 }
 
+// isNullable reports whether a schema permits null, via OpenAPI 3.0 nullable
+// or an OpenAPI 3.1 type list such as [string, null]
+func isNullable(schema *openapi3.Schema) bool {
+	return schema.Nullable || schema.Type.Includes("null")
+}
+
+// primaryType returns the first non-null type of a schema, so that OpenAPI 3.1
+// type lists such as [string, null] generate values of the concrete type
+func primaryType(types *openapi3.Types) string {
+	for _, typ := range types.Slice() {
+		if typ != "null" {
+			return typ
+		}
+	}
+	return ""
+}
+
 // generateObject generates a mock object from schema properties
 func (g *Generator) generateObject(schema *openapi3.Schema, ctx GenerationContext) interface{} {
 	result := make(map[string]interface{}, len(schema.Properties))
@@ -144,18 +227,123 @@ func (g *Generator) generateObject(schema *openapi3.Schema, ctx GenerationContex
 		newParentSchemas = append(ctx.ParentSchemas, schema.Title)
 	}
 
-	for propName, prop := range schema.Properties {
+	// Address objects draw all their components from a single address
+	var address *Address
+	if g.config.UseFieldNameForData && isAddressObject(schema, ctx) {
+		a := g.randomSource.Address()
+		address = &a
+	}
+
+	// Visit properties in a fixed order so that seeded generation is reproducible
+	propNames := make([]string, 0, len(schema.Properties))
+	for propName := range schema.Properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		prop := schema.Properties[propName]
 		if prop.Value != nil {
+			if raw, ok := ctx.PathParams[propName]; ok {
+				if value, ok := pathParamValue(raw, prop.Value); ok {
+					result[propName] = value
+					continue
+				}
+			}
+			if address != nil {
+				if value, ok := addressComponent(*address, propName, prop.Value); ok {
+					result[propName] = value
+					continue
+				}
+			}
 			childCtx := GenerationContext{
 				FieldName:     propName,
 				ParentSchemas: newParentSchemas,
+				Depth:         ctx.Depth + 1,
+				Seed:          childSeed(ctx.Seed, propName),
 			}
 			result[propName] = g.GenerateDataWithContext(prop.Value, childCtx)
 		}
 	}
+
+	if extra := schema.AdditionalProperties.Schema; extra != nil && extra.Value != nil {
+		g.generateMapEntries(schema, extra.Value, ctx, newParentSchemas, result)
+	}
 	return result
 }
 
+// generateMapEntries adds synthetic keys to result with values generated from
+// the additionalProperties schema, kept within minProperties and maxProperties
+func (g *Generator) generateMapEntries(schema, valueSchema *openapi3.Schema, ctx GenerationContext, parentSchemas []string, result map[string]interface{}) {
+	count := max(*g.config.MapEntries, safeUint64ToInt(schema.MinProps)-len(result))
+	if schema.MaxProps != nil {
+		count = min(count, safeUint64ToInt(*schema.MaxProps)-len(result))
+	}
+	for i := 0; i < count; i++ {
+		key := g.randomSource.Word()
+		for n := len(result) + 1; key == "" || hasKey(result, key); n++ {
+			key = fmt.Sprintf("key%d", n)
+		}
+		childCtx := GenerationContext{
+			FieldName:     key,
+			ParentSchemas: parentSchemas,
+			Depth:         ctx.Depth + 1,
+			Seed:          childSeed(ctx.Seed, key),
+		}
+		result[key] = g.GenerateDataWithContext(valueSchema, childCtx)
+	}
+}
+
+// pathParamSeed returns the seed of a resource identified by path parameters
+func pathParamSeed(params map[string]string) string {
+	parts := make([]string, 0, len(params))
+	for name, value := range params {
+		parts = append(parts, name+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "&")
+}
+
+// childSeed returns the seed of a property or item within the value seeded by
+// parent, or an empty seed when parent is empty
+func childSeed(parent, name string) string {
+	if parent == "" {
+		return ""
+	}
+	return parent + "/" + name
+}
+
+// stableUUID derives a version 5 style UUID from seed
+func stableUUID(seed string) string {
+	sum := sha1.Sum([]byte(seed)) // #nosec G401 - used for identifiers, not security
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// hasKey reports whether result already holds key
+func hasKey(result map[string]interface{}, key string) bool {
+	_, ok := result[key]
+	return ok
+}
+
+// pathParamValue converts a path parameter to the type of the property it
+// fills, reporting false when the value does not fit that type
+func pathParamValue(raw string, schema *openapi3.Schema) (interface{}, bool) {
+	switch primaryType(schema.Type) {
+	case "integer":
+		value, err := strconv.ParseInt(raw, 10, 64)
+		return value, err == nil
+	case "number":
+		value, err := strconv.ParseFloat(raw, 64)
+		return value, err == nil
+	case "string", "":
+		return raw, true
+	default:
+		return nil, false
+	}
+}
+
 // generateArray generates a mock array from schema items
 func (g *Generator) generateArray(schema *openapi3.Schema, ctx GenerationContext) interface{} {
 	if schema.Items == nil || schema.Items.Value == nil {
@@ -164,35 +352,149 @@ func (g *Generator) generateArray(schema *openapi3.Schema, ctx GenerationContext
 
 	// Determine array length
 	length := g.config.DefaultArrayLength
-	if schema.MinItems > 0 {
-		length = safeUint64ToInt(schema.MinItems)
-	}
-	if schema.MaxItems != nil && safeUint64ToInt(*schema.MaxItems) < length {
-		length = safeUint64ToInt(*schema.MaxItems)
+	if count, ok := mockCount(schema); ok {
+		// An explicit x-mock-count is still kept within minItems and maxItems
+		length = max(count, safeUint64ToInt(schema.MinItems))
+		if schema.MaxItems != nil && safeUint64ToInt(*schema.MaxItems) < length {
+			length = safeUint64ToInt(*schema.MaxItems)
+		}
+	} else {
+		if schema.MinItems > 0 {
+			length = safeUint64ToInt(schema.MinItems)
+		}
+		if schema.MaxItems != nil && safeUint64ToInt(*schema.MaxItems) < length {
+			length = safeUint64ToInt(*schema.MaxItems)
+		}
+		if schema.MinItems == 0 && schema.MaxItems != nil {
+			// Generate random length between 1 and maxItems
+			length = 1 + g.randIntn(safeUint64ToInt(*schema.MaxItems))
+		}
 	}
-	if schema.MinItems == 0 && schema.MaxItems != nil {
-		// Generate random length between 1 and maxItems
-		length = 1 + g.randIntn(safeUint64ToInt(*schema.MaxItems))
+	if length > g.config.MaxArrayLength {
+		// Protect against adversarial or mistaken specs asking for huge arrays
+		g.config.Logger.Warn("Clamping generated array length",
+			zap.String("field", ctx.FieldName),
+			zap.Int("requested_length", length),
+			zap.Int("max_array_length", g.config.MaxArrayLength),
+		)
+		length = g.config.MaxArrayLength
 	}
 
-	// Generate items
+	// Generate items one level deeper than the array itself
+	itemCtx := ctx
+	itemCtx.Depth++
+	itemCtx.PathParams = nil
+
 	result := make([]interface{}, 0, length)
+	if uniqueBy, ok := schema.Extensions[constants.ExtensionMockUniqueBy].(string); ok && uniqueBy != "" {
+		return g.generateUniqueItems(schema, itemCtx, length, uniqueBy)
+	}
 	if schema.UniqueItems {
-		return g.generateUniqueItems(schema, ctx, length)
+		return g.generateUniqueItems(schema, itemCtx, length, "")
 	}
 
 	for i := 0; i < length; i++ {
-		item := g.GenerateDataWithContext(schema.Items.Value, ctx)
+		itemCtx.Seed = childSeed(ctx.Seed, strconv.Itoa(i))
+		item := g.GenerateDataWithContext(schema.Items.Value, itemCtx)
 		result = append(result, item)
 	}
 
 	return result
 }
 
+// mockCount returns the array length requested by the x-mock-count extension,
+// reporting false when it is absent or not a non-negative integer
+func mockCount(schema *openapi3.Schema) (int, bool) {
+	switch count := schema.Extensions[constants.ExtensionMockCount].(type) {
+	case float64:
+		if count >= 0 && count == math.Trunc(count) && count <= math.MaxInt32 {
+			return int(count), true
+		}
+	case int:
+		if count >= 0 {
+			return count, true
+		}
+	}
+	return 0, false
+}
+
+// selectEnum picks an enum value. With x-mock-weights, values are chosen at
+// random in proportion to the weight at the same position; otherwise every
+// value is equally likely.
+func (g *Generator) selectEnum(schema *openapi3.Schema, ctx GenerationContext) interface{} {
+	raw, ok := schema.Extensions[constants.ExtensionMockWeights]
+	if !ok {
+		return schema.Enum[g.randIntn(len(schema.Enum))]
+	}
+	weights, err := mockWeights(raw, len(schema.Enum))
+	if err != nil {
+		g.config.Logger.Warn("Ignoring invalid enum weights",
+			zap.String("field", ctx.FieldName),
+			zap.Error(err),
+		)
+		return schema.Enum[g.randIntn(len(schema.Enum))]
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	target := g.randFloat64() * total
+	for i, weight := range weights {
+		if target < weight {
+			return schema.Enum[i]
+		}
+		target -= weight
+	}
+	// Rounding can leave a sliver past the last bucket; use the last weighted value
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return schema.Enum[i]
+		}
+	}
+	return schema.Enum[0]
+}
+
+// mockWeights reads the x-mock-weights extension: one non-negative number per
+// enum value, at least one of them positive
+func mockWeights(raw interface{}, count int) ([]float64, error) {
+	values, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of numbers", constants.ExtensionMockWeights)
+	}
+	if len(values) != count {
+		return nil, fmt.Errorf("%s has %d weights for %d enum values", constants.ExtensionMockWeights, len(values), count)
+	}
+
+	weights := make([]float64, len(values))
+	total := 0.0
+	for i, value := range values {
+		switch v := value.(type) {
+		case float64:
+			weights[i] = v
+		case int:
+			weights[i] = float64(v)
+		default:
+			return nil, fmt.Errorf("%s weight %v is not a number", constants.ExtensionMockWeights, value)
+		}
+		if weights[i] < 0 || math.IsNaN(weights[i]) || math.IsInf(weights[i], 0) {
+			return nil, fmt.Errorf("%s weight %v must be a non-negative number", constants.ExtensionMockWeights, value)
+		}
+		total += weights[i]
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("%s needs at least one positive weight", constants.ExtensionMockWeights)
+	}
+	return weights, nil
+}
+
 // generateString generates a mock string value
 // generateString generates a mock string value
 func (g *Generator) generateString(schema *openapi3.Schema, ctx GenerationContext) interface{} {
 	// Priority 3: Format-specific generation
+	if schema.Format == "uuid" && ctx.Seed != "" {
+		return g.applyStringConstraints(stableUUID(ctx.Seed), schema)
+	}
 	if schema.Format != "" {
 		if handler, exists := g.formatHandlers[schema.Format]; exists {
 			result := handler()
@@ -202,8 +504,8 @@ func (g *Generator) generateString(schema *openapi3.Schema, ctx GenerationContex
 
 	// Priority 4: Pattern-based generation
 	if schema.Pattern != "" {
-		if result, err := g.randomSource.GeneratePattern(schema.Pattern, 10); err == nil {
-			return g.applyStringConstraints(result, schema)
+		if result, ok := g.generatePattern(schema, ctx); ok {
+			return result
 		}
 	}
 
@@ -219,6 +521,52 @@ func (g *Generator) generateString(schema *openapi3.Schema, ctx GenerationContex
 	return g.applyStringConstraints(result, schema)
 }
 
+// patternAttempts bounds how many values are generated looking for one that
+// matches a pattern and also fits the length constraints
+const patternAttempts = 10
+
+// generatePattern generates a string matching the schema's pattern. Values
+// are checked against the pattern and retried until one also fits minLength
+// and maxLength; failing that, the last value is adjusted to the lengths.
+// Patterns Go cannot handle, such as backreferences or lookarounds, are
+// logged and reported as not generated.
+func (g *Generator) generatePattern(schema *openapi3.Schema, ctx GenerationContext) (string, bool) {
+	re, err := regexp.Compile(schema.Pattern)
+	if err == nil {
+		var result string
+		for i := 0; i < patternAttempts; i++ {
+			if result, err = g.randomSource.GeneratePattern(schema.Pattern, 10); err != nil {
+				break
+			}
+			if re.MatchString(result) && fitsLength(result, schema) {
+				return result, true
+			}
+		}
+		if err == nil {
+			g.config.Logger.Warn("Generated value may not match pattern within length constraints",
+				zap.String("field", ctx.FieldName),
+				zap.String("pattern", schema.Pattern),
+			)
+			return g.applyStringConstraints(result, schema), true
+		}
+	}
+
+	g.config.Logger.Warn("Unsupported pattern, generating a value that may not match it",
+		zap.String("field", ctx.FieldName),
+		zap.String("pattern", schema.Pattern),
+		zap.Error(err),
+	)
+	return "", false
+}
+
+// fitsLength reports whether str satisfies the schema's minLength and maxLength
+func fitsLength(str string, schema *openapi3.Schema) bool {
+	if schema.MaxLength != nil && uint64(len(str)) > *schema.MaxLength {
+		return false
+	}
+	return uint64(len(str)) >= schema.MinLength
+}
+
 // generateNumber generates a mock number value
 func (g *Generator) generateNumber(schema *openapi3.Schema, ctx GenerationContext) interface{} {
 	// Field name intelligence for realistic ranges
@@ -233,19 +581,25 @@ func (g *Generator) generateNumber(schema *openapi3.Schema, ctx GenerationContex
 	max := 100.0
 
 	// Apply schema constraints
-	if schema.Min != nil {
-		min = *schema.Min
+	if lower, ok := lowerBound(schema); ok {
+		min = lower
 	}
-	if schema.Max != nil {
-		max = *schema.Max
+	if upper, ok := upperBound(schema); ok {
+		max = upper
 	}
 
 	// Generate random value in range
 	val := min + g.randFloat64()*(max-min)
 
-	// Apply multipleOf constraint
+	// Apply multipleOf constraint, stepping back inside the bounds if rounding left them
 	if schema.MultipleOf != nil {
-		val = math.Round(val / *schema.MultipleOf) * *schema.MultipleOf
+		multiple := *schema.MultipleOf
+		val = math.Round(val/multiple) * multiple
+		if val < min {
+			val += multiple
+		} else if val > max {
+			val -= multiple
+		}
 	}
 
 	return val
@@ -253,6 +607,11 @@ func (g *Generator) generateNumber(schema *openapi3.Schema, ctx GenerationContex
 
 // generateInteger generates a mock integer value
 func (g *Generator) generateInteger(schema *openapi3.Schema, ctx GenerationContext) interface{} {
+	// int64 timestamps such as createdAt are Unix epoch milliseconds
+	if schema.Format == "int64" && ctx.FieldName != "" && g.config.UseFieldNameForData && isTimestampField(ctx.FieldName) {
+		return g.generateEpochMillis(schema)
+	}
+
 	// Field name intelligence for realistic ranges
 	if g.config.UseFieldNameForData && ctx.FieldName != "" {
 		if result := g.generateIntegerByFieldName(ctx.FieldName); result != 0 {
@@ -260,16 +619,28 @@ func (g *Generator) generateInteger(schema *openapi3.Schema, ctx GenerationConte
 		}
 	}
 
-	// Default range: 1 to 100
+	// Default range: 1 to 100, or 100 values from an explicit minimum
 	min := 1
 	max := 100
 
 	// Apply schema constraints
-	if schema.Min != nil {
-		min = int(*schema.Min)
+	if lower, ok := intLowerBound(schema); ok {
+		min = lower
+		if schema.Max == nil && min > max {
+			max = min + 99
+		}
 	}
-	if schema.Max != nil {
-		max = int(*schema.Max)
+	if upper, ok := intUpperBound(schema); ok {
+		max = upper
+	}
+
+	// int32 values must fit in 32 bits whatever the declared bounds
+	if schema.Format == "int32" {
+		min = clampInt(min, math.MinInt32, math.MaxInt32)
+		max = clampInt(max, math.MinInt32, math.MaxInt32)
+	}
+	if max < min {
+		max = min
 	}
 
 	// Generate random value in range
@@ -280,15 +651,86 @@ func (g *Generator) generateInteger(schema *openapi3.Schema, ctx GenerationConte
 		multiple := int(*schema.MultipleOf)
 		if multiple > 0 {
 			val = (val / multiple) * multiple
+			if val < min {
+				val += multiple
+			}
 		}
 	}
 
 	return val
 }
 
+// generateEpochMillis returns a Unix timestamp in milliseconds from the past year
+func (g *Generator) generateEpochMillis(schema *openapi3.Schema) int64 {
+	const yearMillis = 365 * 24 * 60 * 60 * 1000
+	val := time.Now().UnixMilli() - int64(g.randIntn(yearMillis))
+
+	if lower, ok := intLowerBound(schema); ok && val < int64(lower) {
+		val = int64(lower)
+	}
+	if upper, ok := intUpperBound(schema); ok && val > int64(upper) {
+		val = int64(upper)
+	}
+	return val
+}
+
+// isTimestampField reports whether a field name suggests a point in time
+func isTimestampField(fieldName string) bool {
+	lowerField := strings.ToLower(fieldName)
+	for _, pattern := range []string{"timestamp", "epoch", "millis"} {
+		if strings.Contains(lowerField, pattern) {
+			return true
+		}
+	}
+	// createdAt, updated_at and similar
+	return strings.HasSuffix(fieldName, "At") || strings.HasSuffix(lowerField, "_at")
+}
+
+// clampInt limits val to the range [min, max]
+func clampInt(val, min, max int) int {
+	if val < min {
+		return min
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
 // generateBoolean generates a mock boolean value
 func (g *Generator) generateBoolean(schema *openapi3.Schema, ctx GenerationContext) interface{} {
-	return g.randIntn(2) == 1
+	probability := 0.5
+	if g.config.BooleanTrueProbability != nil {
+		probability = *g.config.BooleanTrueProbability
+	}
+
+	// Field name intelligence: flags like isActive are usually true, isDeleted usually false
+	if g.config.UseFieldNameForData && ctx.FieldName != "" {
+		if bias, ok := booleanBiasByFieldName(ctx.FieldName); ok {
+			probability = bias
+		}
+	}
+
+	return g.randFloat64() < probability
+}
+
+// booleanBiasByFieldName returns the chance of true implied by a boolean field name
+func booleanBiasByFieldName(fieldName string) (float64, bool) {
+	lowerField := strings.ToLower(fieldName)
+
+	// Checked first so that names like "inactive" and "disabled" are not read as true-leaning
+	for _, pattern := range []string{"inactive", "disabled", "deleted", "archived", "locked", "blocked", "deprecated", "expired"} {
+		if strings.Contains(lowerField, pattern) {
+			return 0.1, true
+		}
+	}
+	for _, pattern := range []string{"active", "enabled", "available", "verified", "visible", "valid", "success"} {
+		if strings.Contains(lowerField, pattern) {
+			return 0.9, true
+		}
+	}
+
+	return 0, false
 }
 
 // initFormatHandlers initializes the format handler registry
@@ -320,7 +762,11 @@ func (g *Generator) applyStringConstraints(str string, schema *openapi3.Schema)
 	if schema.MinLength > 0 && uint64(len(str)) < schema.MinLength {
 		minLen := safeUint64ToInt(schema.MinLength)
 		for len(str) < minLen {
-			str += g.randomSource.Word()
+			word := g.randomSource.Word()
+			if word == "" {
+				word = "x" // Never spin on an empty word
+			}
+			str += word
 		}
 		// Trim to exact length if we overshot
 		if uint64(len(str)) > schema.MinLength {
@@ -335,20 +781,25 @@ func (g *Generator) applyStringConstraints(str string, schema *openapi3.Schema)
 func (g *Generator) generateByFieldName(fieldName string) string {
 	lowerField := strings.ToLower(fieldName)
 
-	fieldHandlers := map[string]func() string{
-		"firstname":  g.randomSource.FirstName,
-		"first_name": g.randomSource.FirstName,
-		"lastname":   g.randomSource.LastName,
-		"last_name":  g.randomSource.LastName,
-		"name":       g.randomSource.Name,
-		"email":      g.randomSource.Email,
-		"phone":      g.randomSource.Phonenumber,
-		"address":    g.randomSource.Sentence,
-		"company":    g.randomSource.Word,
-		"username":   g.randomSource.Username,
+	// Checked in order, so the more specific names win over "name"
+	fieldHandlers := []struct {
+		pattern string
+		handler func() string
+	}{
+		{"firstname", g.randomSource.FirstName},
+		{"first_name", g.randomSource.FirstName},
+		{"lastname", g.randomSource.LastName},
+		{"last_name", g.randomSource.LastName},
+		{"username", g.randomSource.Username},
+		{"name", g.randomSource.Name},
+		{"email", g.randomSource.Email},
+		{"phone", g.randomSource.Phonenumber},
+		{"address", g.randomSource.Sentence},
+		{"company", g.randomSource.Word},
 	}
 
-	for pattern, handler := range fieldHandlers {
+	for _, fh := range fieldHandlers {
+		pattern, handler := fh.pattern, fh.handler
 		if strings.Contains(lowerField, pattern) {
 			// Special case: "name" should not match if "username" is present
 			if pattern == "name" && strings.Contains(lowerField, "user") {
@@ -403,37 +854,96 @@ func (g *Generator) generateIntegerByFieldName(fieldName string) int {
 
 // applyNumberConstraints applies min/max constraints to a number
 func (g *Generator) applyNumberConstraints(val float64, schema *openapi3.Schema) float64 {
-	if schema.Min != nil && val < *schema.Min {
-		val = *schema.Min
+	if lower, ok := lowerBound(schema); ok && val < lower {
+		val = lower
 	}
-	if schema.Max != nil && val > *schema.Max {
-		val = *schema.Max
+	if upper, ok := upperBound(schema); ok && val > upper {
+		val = upper
 	}
 	return val
 }
 
 // applyIntegerConstraints applies min/max constraints to an integer
 func (g *Generator) applyIntegerConstraints(val int, schema *openapi3.Schema) int {
-	if schema.Min != nil && float64(val) < *schema.Min {
-		val = int(*schema.Min)
+	if schema.Format == "int32" {
+		val = clampInt(val, math.MinInt32, math.MaxInt32)
 	}
-	if schema.Max != nil && float64(val) > *schema.Max {
-		val = int(*schema.Max)
+	if lower, ok := intLowerBound(schema); ok && val < lower {
+		val = lower
+	}
+	if upper, ok := intUpperBound(schema); ok && val > upper {
+		val = upper
 	}
 	return val
 }
 
-// generateUniqueItems generates an array with unique items
-func (g *Generator) generateUniqueItems(schema *openapi3.Schema, ctx GenerationContext, length int) []interface{} {
+// lowerBound returns the smallest number the schema allows, just above
+// minimum when exclusiveMinimum is set
+func lowerBound(schema *openapi3.Schema) (float64, bool) {
+	if schema.Min == nil {
+		return 0, false
+	}
+	if schema.ExclusiveMin {
+		return math.Nextafter(*schema.Min, math.Inf(1)), true
+	}
+	return *schema.Min, true
+}
+
+// upperBound returns the largest number the schema allows, just below
+// maximum when exclusiveMaximum is set
+func upperBound(schema *openapi3.Schema) (float64, bool) {
+	if schema.Max == nil {
+		return 0, false
+	}
+	if schema.ExclusiveMax {
+		return math.Nextafter(*schema.Max, math.Inf(-1)), true
+	}
+	return *schema.Max, true
+}
+
+// intLowerBound returns the smallest integer the schema allows
+func intLowerBound(schema *openapi3.Schema) (int, bool) {
+	if schema.Min == nil {
+		return 0, false
+	}
+	min := math.Ceil(*schema.Min)
+	if schema.ExclusiveMin && min == *schema.Min {
+		min++
+	}
+	return int(min), true
+}
+
+// intUpperBound returns the largest integer the schema allows
+func intUpperBound(schema *openapi3.Schema) (int, bool) {
+	if schema.Max == nil {
+		return 0, false
+	}
+	max := math.Floor(*schema.Max)
+	if schema.ExclusiveMax && max == *schema.Max {
+		max--
+	}
+	return int(max), true
+}
+
+// generateUniqueItems generates an array with unique items or, when uniqueBy
+// is set, with object items whose uniqueBy field values are distinct
+func (g *Generator) generateUniqueItems(schema *openapi3.Schema, ctx GenerationContext, length int, uniqueBy string) []interface{} {
 	result := make([]interface{}, 0, length)
 	seen := make(map[string]bool)
 	maxAttempts := length * 10 // Prevent infinite loops
 
-	for len(result) < length && maxAttempts > 0 {
+	parentSeed := ctx.Seed
+	for attempt := 0; len(result) < length && maxAttempts > 0; attempt++ {
+		ctx.Seed = childSeed(parentSeed, strconv.Itoa(attempt))
 		item := g.GenerateDataWithContext(schema.Items.Value, ctx)
 
-		// Create a key for uniqueness checking
-		key := g.getItemKey(item)
+		// Create a key for uniqueness checking, from the item or its uniqueBy field
+		keyValue := item
+		if uniqueBy != "" {
+			object, _ := item.(map[string]interface{})
+			keyValue = object[uniqueBy]
+		}
+		key := g.getItemKey(keyValue)
 		if !seen[key] {
 			seen[key] = true
 			result = append(result, item)
@@ -452,6 +962,8 @@ func (g *Generator) getItemKey(item interface{}) string {
 		return "s:" + v
 	case int:
 		return fmt.Sprintf("i:%d", v)
+	case int64:
+		return fmt.Sprintf("i:%d", v)
 	case float64:
 		return fmt.Sprintf("f:%f", v)
 	case bool:
@@ -465,7 +977,7 @@ func (g *Generator) getItemKey(item interface{}) string {
 }
 
 // mergeSchemas combines multiple schemas for allOf composition support
-func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs) *openapi3.Schema {
+func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs, ctx GenerationContext) *openapi3.Schema {
 	if len(schemas) == 0 {
 		return nil
 	}
@@ -474,7 +986,6 @@ func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs) *openapi3.Schema {
 	}
 
 	merged := &openapi3.Schema{
-		Type:       &openapi3.Types{"object"},
 		Properties: make(map[string]*openapi3.SchemaRef),
 	}
 
@@ -484,6 +995,11 @@ func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs) *openapi3.Schema {
 		}
 		schema := schemaRef.Value
 
+		// Merge type (take first declared)
+		if schema.Type != nil && len(*schema.Type) > 0 && merged.Type == nil {
+			merged.Type = schema.Type
+		}
+
 		// Merge properties
 		if schema.Properties != nil {
 			for propName, propRef := range schema.Properties {
@@ -496,9 +1012,11 @@ func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs) *openapi3.Schema {
 		// Merge constraints (take the most restrictive)
 		if schema.Min != nil && (merged.Min == nil || *schema.Min > *merged.Min) {
 			merged.Min = schema.Min
+			merged.ExclusiveMin = schema.ExclusiveMin
 		}
 		if schema.Max != nil && (merged.Max == nil || *schema.Max < *merged.Max) {
 			merged.Max = schema.Max
+			merged.ExclusiveMax = schema.ExclusiveMax
 		}
 		if schema.MultipleOf != nil && (merged.MultipleOf == nil || *schema.MultipleOf > *merged.MultipleOf) {
 			merged.MultipleOf = schema.MultipleOf
@@ -522,6 +1040,9 @@ func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs) *openapi3.Schema {
 		if schema.UniqueItems {
 			merged.UniqueItems = true
 		}
+		if schema.Items != nil && merged.Items == nil {
+			merged.Items = schema.Items
+		}
 
 		// Merge format and pattern (take first non-empty)
 		if schema.Format != "" && merged.Format == "" {
@@ -538,14 +1059,48 @@ func (g *Generator) mergeSchemas(schemas openapi3.SchemaRefs) *openapi3.Schema {
 				enumSet[e] = true
 			}
 			for _, e := range schema.Enum {
-				enumSet[e] = true
-			}
-			merged.Enum = make([]interface{}, 0, len(enumSet))
-			for e := range enumSet {
-				merged.Enum = append(merged.Enum, e)
+				if !enumSet[e] {
+					enumSet[e] = true
+					merged.Enum = append(merged.Enum, e)
+				}
 			}
 		}
 	}
 
+	if merged.Type == nil {
+		merged.Type = &openapi3.Types{"object"}
+	}
+	g.resolveConflicts(merged, ctx)
 	return merged
 }
+
+// resolveConflicts relaxes constraints that allOf subschemas made impossible to
+// satisfy together, such as a minLength above another subschema's maxLength.
+// Each conflict is logged and resolved in favor of the upper bound, so the
+// generated value still satisfies at least one of the subschemas.
+func (g *Generator) resolveConflicts(merged *openapi3.Schema, ctx GenerationContext) {
+	warn := func(constraint string, min, max interface{}) {
+		g.config.Logger.Warn("Conflicting allOf constraints, generating a best-effort value",
+			zap.String("field", ctx.FieldName),
+			zap.String("constraint", constraint),
+			zap.Any("min", min),
+			zap.Any("max", max),
+		)
+	}
+
+	if merged.MaxLength != nil && merged.MinLength > *merged.MaxLength {
+		warn("length", merged.MinLength, *merged.MaxLength)
+		merged.MinLength = *merged.MaxLength
+	}
+	if merged.Min != nil && merged.Max != nil && *merged.Min > *merged.Max {
+		warn("value", *merged.Min, *merged.Max)
+		// Exclusive bounds would push the collapsed range apart again
+		merged.Min = merged.Max
+		merged.ExclusiveMin = false
+		merged.ExclusiveMax = false
+	}
+	if merged.MaxItems != nil && merged.MinItems > *merged.MaxItems {
+		warn("items", merged.MinItems, *merged.MaxItems)
+		merged.MinItems = *merged.MaxItems
+	}
+}
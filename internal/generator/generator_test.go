@@ -1,12 +1,17 @@
 package generator
 
 import (
+	"math"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestNewGenerator tests the creation of a new Generator.
@@ -115,6 +120,31 @@ func TestGenerateDataFromNumber(t *testing.T) {
 		remainder := val / multipleOf
 		assert.Equal(t, remainder, float64(int(remainder)))
 	})
+
+	t.Run("Exclusive bounds", func(t *testing.T) {
+		min, max := 0.0, 1.0
+		schema := &openapi3.Schema{
+			Type:         &openapi3.Types{"number"},
+			Min:          &min,
+			Max:          &max,
+			ExclusiveMin: true,
+			ExclusiveMax: true,
+		}
+		positive := &openapi3.Schema{Type: &openapi3.Types{"number"}, Min: &min, ExclusiveMin: true}
+		priced := &openapi3.Schema{Type: &openapi3.Types{"number"}, Max: &max, ExclusiveMax: true}
+		fieldGen := New(Config{UseFieldNameForData: true})
+		for i := 0; i < 200; i++ {
+			val := g.GenerateData(schema).(float64)
+			assert.Greater(t, val, min)
+			assert.Less(t, val, max)
+
+			assert.Greater(t, g.GenerateData(positive).(float64), min)
+
+			// Field name ranges are clamped inside exclusive bounds too
+			price := fieldGen.GenerateDataWithContext(priced, GenerationContext{FieldName: "price"}).(float64)
+			assert.Less(t, price, max)
+		}
+	})
 }
 
 // TestGenerateDataFromInteger tests integer data generation.
@@ -141,6 +171,69 @@ func TestGenerateDataFromInteger(t *testing.T) {
 		assert.GreaterOrEqual(t, val, int(min))
 		assert.LessOrEqual(t, val, int(max))
 	})
+
+	t.Run("Minimum without maximum", func(t *testing.T) {
+		min := float64(500)
+		schema := &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &min}
+		for i := 0; i < 50; i++ {
+			val, ok := g.GenerateData(schema).(int)
+			require.True(t, ok)
+			assert.GreaterOrEqual(t, val, 500)
+		}
+	})
+
+	t.Run("Exclusive bounds", func(t *testing.T) {
+		min, max := float64(0), float64(3)
+		schema := &openapi3.Schema{
+			Type:         &openapi3.Types{"integer"},
+			Min:          &min,
+			Max:          &max,
+			ExclusiveMin: true,
+			ExclusiveMax: true,
+		}
+		multipleOf := float64(5)
+		positive := &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &min, ExclusiveMin: true, MultipleOf: &multipleOf}
+		for i := 0; i < 200; i++ {
+			val := g.GenerateData(schema).(int)
+			assert.Greater(t, val, 0)
+			assert.Less(t, val, 3)
+
+			val = g.GenerateData(positive).(int)
+			assert.Greater(t, val, 0)
+			assert.Zero(t, val%5)
+		}
+	})
+
+	t.Run("int32 stays within range", func(t *testing.T) {
+		min := float64(math.MaxInt32 - 10)
+		max := float64(1 << 40)
+		bounded := &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int32", Min: &min, Max: &max}
+		unbounded := &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int32", Min: &min}
+		for i := 0; i < 100; i++ {
+			for _, schema := range []*openapi3.Schema{bounded, unbounded} {
+				val, ok := g.GenerateData(schema).(int)
+				require.True(t, ok)
+				assert.LessOrEqual(t, val, math.MaxInt32)
+				assert.GreaterOrEqual(t, val, math.MaxInt32-10)
+			}
+		}
+	})
+
+	t.Run("int64 timestamps are epoch millis", func(t *testing.T) {
+		fieldGen := New(Config{UseFieldNameForData: true})
+		schema := &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64"}
+		for _, field := range []string{"timestamp", "createdAt", "updated_at"} {
+			data := fieldGen.GenerateDataWithContext(schema, GenerationContext{FieldName: field})
+			val, ok := data.(int64)
+			require.True(t, ok, "field %s", field)
+			assert.Greater(t, val, time.Now().AddDate(-2, 0, 0).UnixMilli(), "field %s", field)
+			assert.LessOrEqual(t, val, time.Now().UnixMilli(), "field %s", field)
+		}
+
+		// Without int64 the usual ranges apply
+		data := fieldGen.GenerateDataWithContext(&openapi3.Schema{Type: &openapi3.Types{"integer"}}, GenerationContext{FieldName: "timestamp"})
+		assert.IsType(t, 0, data)
+	})
 }
 
 // TestGenerateDataFromBoolean tests boolean data generation.
@@ -211,6 +304,38 @@ func TestGenerateDataFromArray(t *testing.T) {
 	})
 }
 
+// TestGenerateDataArrayLengthCap tests that generated arrays are clamped to MaxArrayLength.
+func TestGenerateDataArrayLengthCap(t *testing.T) {
+	t.Run("Clamps large minItems", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{MaxArrayLength: 50, Logger: zap.New(core)})
+		schema := &openapi3.Schema{
+			Type:     &openapi3.Types{"array"},
+			Items:    &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+			MinItems: 10000,
+		}
+		data := g.GenerateData(schema)
+		arr, ok := data.([]interface{})
+		require.True(t, ok)
+		assert.Len(t, arr, 50)
+		assert.Equal(t, 1, logs.FilterMessage("Clamping generated array length").Len())
+	})
+
+	t.Run("Default cap", func(t *testing.T) {
+		g := New(Config{})
+		assert.Equal(t, DefaultMaxArrayLength, g.config.MaxArrayLength)
+
+		schema := &openapi3.Schema{
+			Type:     &openapi3.Types{"array"},
+			Items:    &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"boolean"}}},
+			MinItems: 5000,
+		}
+		arr, ok := g.GenerateData(schema).([]interface{})
+		require.True(t, ok)
+		assert.Len(t, arr, DefaultMaxArrayLength)
+	})
+}
+
 // TestGenerateDataFromObject tests object data generation.
 func TestGenerateDataFromObject(t *testing.T) {
 	g := New(Config{})
@@ -251,7 +376,61 @@ func TestGenerateDataWithEnum(t *testing.T) {
 		Enum: enum,
 	}
 	data := g.GenerateData(schema)
-	assert.Equal(t, "one", data) // Should pick the first one
+	assert.Contains(t, enum, data)
+}
+
+// TestGenerateDataWithWeightedEnum tests enum selection biased by x-mock-weights.
+func TestGenerateDataWithWeightedEnum(t *testing.T) {
+	weighted := func(weights ...interface{}) *openapi3.Schema {
+		return &openapi3.Schema{
+			Type:       &openapi3.Types{"string"},
+			Enum:       []interface{}{"active", "suspended", "deleted"},
+			Extensions: map[string]interface{}{"x-mock-weights": weights},
+		}
+	}
+
+	t.Run("Distribution follows weights", func(t *testing.T) {
+		g := New(Config{Seed: 7})
+		schema := weighted(90.0, 10.0, 0.0)
+		counts := map[interface{}]int{}
+		const samples = 10000
+		for i := 0; i < samples; i++ {
+			counts[g.GenerateData(schema)]++
+		}
+		assert.InDelta(t, 0.9, float64(counts["active"])/samples, 0.03)
+		assert.InDelta(t, 0.1, float64(counts["suspended"])/samples, 0.03)
+		assert.Zero(t, counts["deleted"], "zero-weight values should never be chosen")
+	})
+
+	t.Run("Without weights every value is equally likely", func(t *testing.T) {
+		g := New(Config{Seed: 7})
+		schema := &openapi3.Schema{
+			Type: &openapi3.Types{"string"},
+			Enum: []interface{}{"active", "suspended", "deleted"},
+		}
+		counts := map[interface{}]int{}
+		const samples = 9000
+		for i := 0; i < samples; i++ {
+			counts[g.GenerateData(schema)]++
+		}
+		for _, value := range schema.Enum {
+			assert.InDelta(t, 1.0/3, float64(counts[value])/samples, 0.03, "share of %v", value)
+		}
+	})
+
+	t.Run("Invalid weights fall back to uniform selection", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		for _, schema := range []*openapi3.Schema{
+			weighted(1.0, 2.0),
+			weighted(1.0, -1.0, 1.0),
+			weighted(0.0, 0.0, 0.0),
+			weighted("high", "low", "none"),
+		} {
+			assert.Contains(t, schema.Enum, g.GenerateDataWithContext(schema, GenerationContext{FieldName: "status"}))
+		}
+		assert.Equal(t, 4, logs.Len())
+	})
 }
 
 // TestGenerateDataWithComposition tests schema composition.
@@ -311,6 +490,106 @@ func TestGenerateDataWithComposition(t *testing.T) {
 	})
 }
 
+// TestAllOfConflictingConstraints tests that contradictory allOf constraints are
+// reported and resolved to a best-effort value.
+func TestAllOfConflictingConstraints(t *testing.T) {
+	float := func(v float64) *float64 { return &v }
+	uint := func(v uint64) *uint64 { return &v }
+	allOf := func(schemas ...*openapi3.Schema) *openapi3.Schema {
+		refs := make(openapi3.SchemaRefs, 0, len(schemas))
+		for _, s := range schemas {
+			refs = append(refs, &openapi3.SchemaRef{Value: s})
+		}
+		return &openapi3.Schema{AllOf: refs}
+	}
+
+	t.Run("Contradictory lengths", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		schema := allOf(
+			&openapi3.Schema{Type: &openapi3.Types{"string"}, MaxLength: uint(5)},
+			&openapi3.Schema{MinLength: 10},
+		)
+		data := g.GenerateDataWithContext(schema, GenerationContext{FieldName: "code"})
+		require.IsType(t, "", data)
+		assert.Len(t, data.(string), 5)
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, "length", fields["constraint"])
+		assert.Equal(t, "code", fields["field"])
+	})
+
+	t.Run("Contradictory minimum and maximum", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		integer := allOf(
+			&openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: float(50)},
+			&openapi3.Schema{Max: float(10)},
+		)
+		assert.Equal(t, 10, g.GenerateData(integer))
+
+		number := allOf(
+			&openapi3.Schema{Type: &openapi3.Types{"number"}, Min: float(2.5)},
+			&openapi3.Schema{Max: float(1.5)},
+		)
+		assert.Equal(t, 1.5, g.GenerateData(number))
+
+		require.Equal(t, 2, logs.Len())
+		assert.Equal(t, "value", logs.All()[0].ContextMap()["constraint"])
+	})
+
+	t.Run("Contradictory exclusive minimum and maximum", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		integer := allOf(
+			&openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: float(10), ExclusiveMin: true},
+			&openapi3.Schema{Max: float(5)},
+		)
+		assert.Equal(t, 5, g.GenerateData(integer))
+
+		number := allOf(
+			&openapi3.Schema{Type: &openapi3.Types{"number"}, Min: float(2.5), ExclusiveMin: true},
+			&openapi3.Schema{Max: float(1.5), ExclusiveMax: true},
+		)
+		assert.Equal(t, 1.5, g.GenerateData(number))
+
+		require.Equal(t, 2, logs.Len())
+		assert.Equal(t, "value", logs.All()[0].ContextMap()["constraint"])
+	})
+
+	t.Run("Contradictory item counts", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		schema := allOf(
+			&openapi3.Schema{
+				Type:  &openapi3.Types{"array"},
+				Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+			},
+			&openapi3.Schema{MinItems: 4, MaxItems: uint(4)},
+			&openapi3.Schema{MaxItems: uint(2)},
+		)
+		data := g.GenerateData(schema)
+		require.IsType(t, []interface{}{}, data)
+		assert.Len(t, data.([]interface{}), 2)
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, "items", logs.All()[0].ContextMap()["constraint"])
+	})
+
+	t.Run("Compatible constraints do not warn", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		schema := allOf(
+			&openapi3.Schema{Type: &openapi3.Types{"string"}, MinLength: 3},
+			&openapi3.Schema{MaxLength: uint(8)},
+		)
+		data := g.GenerateData(schema)
+		require.IsType(t, "", data)
+		assert.GreaterOrEqual(t, len(data.(string)), 3)
+		assert.LessOrEqual(t, len(data.(string)), 8)
+		assert.Equal(t, 0, logs.Len())
+	})
+}
+
 // TestCircularReference tests prevention of infinite recursion.
 func TestCircularReference(t *testing.T) {
 	g := New(Config{})
@@ -331,6 +610,50 @@ func TestCircularReference(t *testing.T) {
 	assert.Nil(t, obj["child"])
 }
 
+func TestCircularReferenceWithoutTitle(t *testing.T) {
+	g := New(Config{})
+
+	// A self-referential schema without a title cannot be caught by the title check
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: map[string]*openapi3.SchemaRef{
+			"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"next": {Value: nil}, // Placeholder
+		},
+	}
+	schema.Properties["next"].Value = schema
+
+	data := g.GenerateData(schema)
+
+	depth := 0
+	for node, ok := data.(map[string]interface{}); ok; node, ok = node["next"].(map[string]interface{}) {
+		depth++
+	}
+	assert.Equal(t, MaxGenerationDepth+1, depth)
+}
+
+func TestCircularReferenceThroughComposition(t *testing.T) {
+	g := New(Config{})
+
+	// Node: {oneOf: [{$ref: Node}]} and the same through allOf and anyOf never
+	// reach generateObject, so only the composition depth stops them
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		t.Run(keyword, func(t *testing.T) {
+			node := &openapi3.Schema{}
+			self := openapi3.SchemaRefs{{Value: node}}
+			switch keyword {
+			case "allOf":
+				node.AllOf = self
+			case "oneOf":
+				node.OneOf = self
+			case "anyOf":
+				node.AnyOf = self
+			}
+			assert.Nil(t, g.GenerateData(node))
+		})
+	}
+}
+
 // TestFieldNameIntelligence tests data generation based on field names.
 func TestFieldNameIntelligence(t *testing.T) {
 	g := New(Config{UseFieldNameForData: true})
@@ -392,3 +715,406 @@ func TestFieldNameIntelligence(t *testing.T) {
 		})
 	}
 }
+
+// fixedAddressSource returns the same address every time so components can be compared.
+type fixedAddressSource struct {
+	*SecureRandomSource
+	address Address
+}
+
+func (s fixedAddressSource) Address() Address {
+	return s.address
+}
+
+// TestAddressObjectIntelligence tests that address objects get consistent components.
+func TestAddressObjectIntelligence(t *testing.T) {
+	stringProp := func() *openapi3.SchemaRef {
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}
+	}
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"street":     stringProp(),
+			"city":       stringProp(),
+			"state":      stringProp(),
+			"zip":        stringProp(),
+			"country":    stringProp(),
+			"unitNumber": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+		},
+	}
+
+	t.Run("Components come from one address", func(t *testing.T) {
+		g := New(Config{UseFieldNameForData: true})
+		address := Address{Street: "1528 Stafford Avenue", City: "Hayward", State: "CA", PostalCode: "94541", Country: "United States"}
+		g.randomSource = fixedAddressSource{SecureRandomSource: NewSecureRandomSource(), address: address}
+
+		data, ok := g.GenerateDataWithContext(schema, GenerationContext{FieldName: "shippingAddress"}).(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, address.Street, data["street"])
+		assert.Equal(t, address.City, data["city"])
+		assert.Equal(t, address.State, data["state"])
+		assert.Equal(t, address.PostalCode, data["zip"])
+		assert.Equal(t, address.Country, data["country"])
+		assert.IsType(t, 0, data["unitNumber"])
+	})
+
+	t.Run("Plausible real address", func(t *testing.T) {
+		g := New(Config{UseFieldNameForData: true})
+		data, ok := g.GenerateDataWithContext(schema, GenerationContext{FieldName: "address"}).(map[string]interface{})
+		require.True(t, ok)
+		assert.NotEmpty(t, data["street"])
+		assert.NotEmpty(t, data["city"])
+		assert.Regexp(t, `^[A-Z]{2}$`, data["state"])
+		assert.Regexp(t, `^\d{5}$`, data["zip"])
+		assert.Equal(t, "United States", data["country"])
+	})
+
+	t.Run("Other objects are unaffected", func(t *testing.T) {
+		g := New(Config{UseFieldNameForData: true})
+		g.randomSource = fixedAddressSource{SecureRandomSource: NewSecureRandomSource(), address: Address{City: "Hayward"}}
+
+		data, ok := g.GenerateDataWithContext(schema, GenerationContext{FieldName: "profile"}).(map[string]interface{})
+		require.True(t, ok)
+		assert.NotEqual(t, "Hayward", data["city"])
+	})
+}
+
+// TestNullableGeneration tests that nullable schemas sometimes generate null.
+func TestNullableGeneration(t *testing.T) {
+	countNulls := func(g *Generator, schema *openapi3.Schema, runs int) int {
+		nulls := 0
+		for i := 0; i < runs; i++ {
+			data := g.GenerateDataWithContext(schema, GenerationContext{FieldName: "nickname"})
+			if data == nil {
+				nulls++
+			} else {
+				assert.IsType(t, "", data)
+			}
+		}
+		return nulls
+	}
+
+	t.Run("OpenAPI 3.0 nullable", func(t *testing.T) {
+		g := New(Config{NullProbability: 0.5})
+		schema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Nullable: true}
+		nulls := countNulls(g, schema, 200)
+		assert.Greater(t, nulls, 0)
+		assert.Less(t, nulls, 200)
+	})
+
+	t.Run("OpenAPI 3.1 type list", func(t *testing.T) {
+		g := New(Config{NullProbability: 0.5})
+		schema := &openapi3.Schema{Type: &openapi3.Types{"string", "null"}}
+		nulls := countNulls(g, schema, 200)
+		assert.Greater(t, nulls, 0)
+		assert.Less(t, nulls, 200)
+	})
+
+	t.Run("Zero probability never generates null", func(t *testing.T) {
+		g := New(Config{})
+		schema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Nullable: true}
+		assert.Equal(t, 0, countNulls(g, schema, 100))
+	})
+
+	t.Run("Non-nullable never generates null", func(t *testing.T) {
+		g := New(Config{NullProbability: 1})
+		schema := &openapi3.Schema{Type: &openapi3.Types{"string"}}
+		assert.Equal(t, 0, countNulls(g, schema, 100))
+	})
+}
+
+// TestTypeListGeneration tests OpenAPI 3.1 type lists that mix a type with null.
+func TestTypeListGeneration(t *testing.T) {
+	t.Run("First non-null type is generated", func(t *testing.T) {
+		g := New(Config{})
+		for _, types := range []openapi3.Types{{"integer", "null"}, {"null", "integer"}} {
+			data := g.GenerateData(&openapi3.Schema{Type: &types})
+			assert.IsType(t, 0, data, "type %v", types)
+		}
+	})
+
+	t.Run("Null is honored for nullable type lists", func(t *testing.T) {
+		g := New(Config{NullProbability: 1})
+		data := g.GenerateData(&openapi3.Schema{Type: &openapi3.Types{"integer", "null"}})
+		assert.Nil(t, data)
+	})
+
+	t.Run("Null-only type generates null", func(t *testing.T) {
+		g := New(Config{})
+		data := g.GenerateData(&openapi3.Schema{Type: &openapi3.Types{"null"}})
+		assert.Nil(t, data)
+	})
+}
+
+// TestPathParamSubstitution tests that path parameters fill matching properties.
+func TestPathParamSubstitution(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"id":    {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+			"slug":  {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"owner": {Value: &openapi3.Schema{Type: &openapi3.Types{"object"}, Properties: openapi3.Schemas{"id": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}}}},
+		},
+	}
+	g := New(Config{})
+
+	t.Run("Matching properties take the path value", func(t *testing.T) {
+		data := g.GenerateDataWithContext(schema, GenerationContext{PathParams: map[string]string{"id": "42", "slug": "rex"}}).(map[string]interface{})
+		assert.Equal(t, int64(42), data["id"])
+		assert.Equal(t, "rex", data["slug"])
+		assert.NotEqual(t, "42", data["owner"].(map[string]interface{})["id"], "nested objects are not substituted")
+	})
+
+	t.Run("Values that do not fit the type are generated", func(t *testing.T) {
+		data := g.GenerateDataWithContext(schema, GenerationContext{PathParams: map[string]string{"id": "abc"}}).(map[string]interface{})
+		assert.IsType(t, 0, data["id"])
+	})
+}
+
+// TestStableUUIDs tests that UUIDs are derived from path parameters when enabled.
+func TestStableUUIDs(t *testing.T) {
+	uuidSchema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}
+	schema := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"uuid":      {Value: uuidSchema},
+			"accountId": {Value: uuidSchema},
+			"tags": {Value: &openapi3.Schema{
+				Type:  &openapi3.Types{"array"},
+				Items: &openapi3.SchemaRef{Value: uuidSchema},
+			}},
+		},
+	}
+	generate := func(g *Generator, params map[string]string) map[string]interface{} {
+		return g.GenerateDataWithContext(schema, GenerationContext{PathParams: params}).(map[string]interface{})
+	}
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	g := New(Config{StableUUIDs: true})
+
+	t.Run("Same path parameters give the same UUIDs", func(t *testing.T) {
+		first := generate(g, map[string]string{"userId": "42"})
+		assert.Equal(t, first, generate(g, map[string]string{"userId": "42"}))
+		assert.Regexp(t, uuidPattern, first["uuid"])
+		assert.NotEqual(t, first["uuid"], first["accountId"], "fields get distinct UUIDs")
+		tags := first["tags"].([]interface{})
+		require.Len(t, tags, 2)
+		assert.NotEqual(t, tags[0], tags[1], "array items get distinct UUIDs")
+	})
+
+	t.Run("Different path parameters give different UUIDs", func(t *testing.T) {
+		assert.NotEqual(t, generate(g, map[string]string{"userId": "42"})["uuid"], generate(g, map[string]string{"userId": "43"})["uuid"])
+	})
+
+	t.Run("Random without path parameters or when disabled", func(t *testing.T) {
+		assert.NotEqual(t, generate(g, nil)["uuid"], generate(g, nil)["uuid"])
+		disabled := New(Config{})
+		assert.NotEqual(t, generate(disabled, map[string]string{"userId": "42"})["uuid"], generate(disabled, map[string]string{"userId": "42"})["uuid"])
+	})
+}
+
+// TestUniqueByGeneration tests that x-mock-unique-by dedupes array items by a field.
+func TestUniqueByGeneration(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type:       &openapi3.Types{"array"},
+		Extensions: map[string]interface{}{"x-mock-unique-by": "id"},
+		Items: &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type: &openapi3.Types{"object"},
+			Properties: openapi3.Schemas{
+				"id":   {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: openapi3.Float64Ptr(1), Max: openapi3.Float64Ptr(30)}},
+				"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			},
+		}},
+	}
+	g := New(Config{DefaultArrayLength: 20})
+
+	for run := 0; run < 10; run++ {
+		items := g.GenerateData(schema).([]interface{})
+		require.Len(t, items, 20)
+
+		seen := make(map[interface{}]bool, len(items))
+		for _, item := range items {
+			id := item.(map[string]interface{})["id"]
+			assert.False(t, seen[id], "duplicate id %v", id)
+			seen[id] = true
+		}
+	}
+}
+
+// TestMockCountGeneration tests that x-mock-count sets the generated array length.
+func TestMockCountGeneration(t *testing.T) {
+	array := func(count interface{}, maxItems *uint64) *openapi3.Schema {
+		return &openapi3.Schema{
+			Type:       &openapi3.Types{"array"},
+			Extensions: map[string]interface{}{"x-mock-count": count},
+			MaxItems:   maxItems,
+			Items:      &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+		}
+	}
+	maxItems := uint64(5)
+
+	tests := []struct {
+		name   string
+		config Config
+		schema *openapi3.Schema
+		want   int
+	}{
+		{"Count overrides the default length", Config{}, array(float64(25), nil), 25},
+		{"Integer count", Config{}, array(3, nil), 3},
+		{"Zero count", Config{}, array(float64(0), nil), 0},
+		{"Clamped by maxItems", Config{}, array(float64(25), &maxItems), 5},
+		{"Clamped by max_array_length", Config{MaxArrayLength: 10}, array(float64(25), nil), 10},
+		{"Invalid count uses the default", Config{}, array("many", nil), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := New(tt.config).GenerateData(tt.schema)
+			assert.Len(t, data, tt.want)
+		})
+	}
+}
+
+// TestPatternGeneration tests generation for anchored, alternating, and unsupported patterns.
+func TestPatternGeneration(t *testing.T) {
+	t.Run("Anchored alternation with bounded quantifiers", func(t *testing.T) {
+		g := New(Config{})
+		for _, pattern := range []string{`^(cat|dog)-\d{3}$`, `^[A-Z]{2,3}_[a-f0-9]{4}$`, `\A(?:red|green|blue)\z`} {
+			re := regexp.MustCompile(pattern)
+			schema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Pattern: pattern}
+			for i := 0; i < 50; i++ {
+				data := g.GenerateData(schema).(string)
+				assert.True(t, re.MatchString(data), "%q does not match %s", data, pattern)
+			}
+		}
+	})
+
+	t.Run("Pattern values respect maxLength", func(t *testing.T) {
+		maxLength := uint64(6)
+		schema := &openapi3.Schema{Type: &openapi3.Types{"string"}, Pattern: `^[a-z]{1,12}$`, MaxLength: &maxLength}
+		g := New(Config{})
+		for i := 0; i < 50; i++ {
+			assert.LessOrEqual(t, len(g.GenerateData(schema).(string)), 6)
+		}
+	})
+
+	t.Run("Unsupported pattern logs a warning", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		g := New(Config{Logger: zap.New(core)})
+		data := g.GenerateData(&openapi3.Schema{Type: &openapi3.Types{"string"}, Pattern: `^(a)\1$`})
+		assert.IsType(t, "", data)
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, `^(a)\1$`, logs.All()[0].ContextMap()["pattern"])
+	})
+}
+
+// TestBooleanBias tests the configured and field-name biases for booleans.
+func TestBooleanBias(t *testing.T) {
+	schema := &openapi3.Schema{Type: &openapi3.Types{"boolean"}}
+	trueRate := func(g *Generator, fieldName string) float64 {
+		const runs = 2000
+		trues := 0
+		for i := 0; i < runs; i++ {
+			if g.GenerateDataWithContext(schema, GenerationContext{FieldName: fieldName}).(bool) {
+				trues++
+			}
+		}
+		return float64(trues) / runs
+	}
+
+	probability := 0.8
+	g := New(Config{UseFieldNameForData: true, BooleanTrueProbability: &probability})
+
+	t.Run("Configured probability", func(t *testing.T) {
+		assert.InDelta(t, 0.8, trueRate(g, "flag"), 0.05)
+	})
+
+	t.Run("True-leaning field name", func(t *testing.T) {
+		assert.InDelta(t, 0.9, trueRate(g, "isActive"), 0.05)
+	})
+
+	t.Run("False-leaning field name", func(t *testing.T) {
+		assert.InDelta(t, 0.1, trueRate(g, "is_deleted"), 0.05)
+		assert.InDelta(t, 0.1, trueRate(g, "inactive"), 0.05)
+	})
+
+	t.Run("Fair coin by default", func(t *testing.T) {
+		assert.InDelta(t, 0.5, trueRate(New(Config{}), "flag"), 0.05)
+	})
+}
+
+// TestAdditionalPropertiesGeneration tests synthetic keys for open map schemas.
+func TestAdditionalPropertiesGeneration(t *testing.T) {
+	mapSchema := func() *openapi3.Schema {
+		return &openapi3.Schema{
+			Type: &openapi3.Types{"object"},
+			AdditionalProperties: openapi3.AdditionalProperties{
+				Schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"string"}}),
+			},
+		}
+	}
+
+	t.Run("String-valued map", func(t *testing.T) {
+		result, ok := New(Config{}).GenerateData(mapSchema()).(map[string]interface{})
+		require.True(t, ok)
+		assert.Len(t, result, DefaultMapEntries)
+		for key, value := range result {
+			assert.NotEmpty(t, key)
+			assert.IsType(t, "", value)
+		}
+	})
+
+	t.Run("Configured entry count", func(t *testing.T) {
+		entries := 5
+		result := New(Config{MapEntries: &entries}).GenerateData(mapSchema()).(map[string]interface{})
+		assert.Len(t, result, 5)
+	})
+
+	t.Run("Zero entries", func(t *testing.T) {
+		entries := 0
+		result := New(Config{MapEntries: &entries}).GenerateData(mapSchema()).(map[string]interface{})
+		assert.Empty(t, result)
+	})
+
+	t.Run("Declared properties are kept", func(t *testing.T) {
+		schema := mapSchema()
+		schema.Properties = openapi3.Schemas{
+			"id": openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"integer"}}),
+		}
+		result := New(Config{}).GenerateData(schema).(map[string]interface{})
+		assert.Contains(t, result, "id")
+		assert.Len(t, result, 1+DefaultMapEntries)
+	})
+
+	t.Run("Within minProperties and maxProperties", func(t *testing.T) {
+		schema := mapSchema()
+		schema.MinProps = 4
+		assert.Len(t, New(Config{}).GenerateData(schema), 4)
+
+		maxProps := uint64(1)
+		schema = mapSchema()
+		schema.MaxProps = &maxProps
+		assert.Len(t, New(Config{}).GenerateData(schema), 1)
+	})
+
+	t.Run("Closed objects get no extra keys", func(t *testing.T) {
+		schema := &openapi3.Schema{Type: &openapi3.Types{"object"}}
+		assert.Empty(t, New(Config{}).GenerateData(schema))
+	})
+}
+
+// TestSeededRandomSourceIsSelfContained tests that seeded sources repeat their
+// own output without reseeding faker for the rest of the process.
+func TestSeededRandomSourceIsSelfContained(t *testing.T) {
+	first := NewSeededRandomSource(42)
+	second := NewSeededRandomSource(42)
+	assert.Equal(t, first.Email(), second.Email())
+	assert.Equal(t, first.UUIDHyphenated(), second.UUIDHyphenated())
+	assert.Equal(t, first.Address(), second.Address())
+
+	// Faker's own sources stay random after seeded calls with the same seed
+	_ = NewSeededRandomSource(7).Word()
+	afterFirst := faker.UUIDHyphenated()
+	_ = NewSeededRandomSource(7).Word()
+	afterSecond := faker.UUIDHyphenated()
+	assert.NotEqual(t, afterFirst, afterSecond)
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"math"
 	"math/big"
+	mathrand "math/rand"
+	"sync"
 	"time"
 
 	"github.com/go-faker/faker/v4"
@@ -31,6 +33,7 @@ type RandomSource interface {
 	DomainName() string
 	IPv4() string
 	IPv6() string
+	Address() Address
 
 	// Pattern generation
 	GeneratePattern(pattern string, maxLength int) (string, error)
@@ -40,8 +43,49 @@ type RandomSource interface {
 	DateTime() string
 }
 
+// Address holds the components of a single, internally consistent postal address
+type Address struct {
+	Street      string
+	City        string
+	State       string
+	PostalCode  string
+	Country     string
+	CountryCode string
+	Latitude    float64
+	Longitude   float64
+}
+
 // SecureRandomSource implements RandomSource with cryptographically secure randomness
-type SecureRandomSource struct{}
+type SecureRandomSource struct {
+	// seeded, when set, makes faker calls reproducible; see SeededRandomSource
+	seeded *mathrand.Rand
+}
+
+// fakerMu guards faker's package-level random sources. Seeded sources swap
+// them for the duration of a single call and restore them afterwards; other
+// calls share the lock so they never draw from a seeded sequence.
+var fakerMu sync.RWMutex
+
+// callFaker runs a faker function. With a seed, faker's sources are derived
+// from it for the call, so the result repeats for the same seed, and are then
+// reset to fresh random ones like faker's own defaults.
+func callFaker[T any](seeded *mathrand.Rand, fn func() T) T {
+	if seeded == nil {
+		fakerMu.RLock()
+		defer fakerMu.RUnlock()
+		return fn()
+	}
+
+	fakerMu.Lock()
+	defer fakerMu.Unlock()
+	faker.SetRandomSource(faker.NewSafeSource(mathrand.NewSource(seeded.Int63())))
+	faker.SetCryptoSource(mathrand.New(mathrand.NewSource(seeded.Int63()))) // #nosec G404 - reproducible mock data, not security
+	defer func() {
+		faker.SetRandomSource(faker.NewSafeSource(mathrand.NewSource(time.Now().UnixNano())))
+		faker.SetCryptoSource(rand.Reader)
+	}()
+	return fn()
+}
 
 // NewSecureRandomSource creates a new secure random source
 func NewSecureRandomSource() *SecureRandomSource {
@@ -67,55 +111,69 @@ func (s *SecureRandomSource) Int() int {
 }
 
 func (s *SecureRandomSource) Email() string {
-	return faker.Email()
+	return callFaker(s.seeded, func() string { return faker.Email() })
 }
 
 func (s *SecureRandomSource) FirstName() string {
-	return faker.FirstName()
+	return callFaker(s.seeded, func() string { return faker.FirstName() })
 }
 
 func (s *SecureRandomSource) LastName() string {
-	return faker.LastName()
+	return callFaker(s.seeded, func() string { return faker.LastName() })
 }
 
 func (s *SecureRandomSource) Name() string {
-	return faker.Name()
+	return callFaker(s.seeded, func() string { return faker.Name() })
 }
 
 func (s *SecureRandomSource) Username() string {
-	return faker.Username()
+	return callFaker(s.seeded, func() string { return faker.Username() })
 }
 
 func (s *SecureRandomSource) Phonenumber() string {
-	return faker.Phonenumber()
+	return callFaker(s.seeded, func() string { return faker.Phonenumber() })
 }
 
 func (s *SecureRandomSource) Sentence() string {
-	return faker.Sentence()
+	return callFaker(s.seeded, func() string { return faker.Sentence() })
 }
 
 func (s *SecureRandomSource) Word() string {
-	return faker.Word()
+	return callFaker(s.seeded, func() string { return faker.Word() })
 }
 
 func (s *SecureRandomSource) UUIDHyphenated() string {
-	return faker.UUIDHyphenated()
+	return callFaker(s.seeded, func() string { return faker.UUIDHyphenated() })
 }
 
 func (s *SecureRandomSource) URL() string {
-	return faker.URL()
+	return callFaker(s.seeded, func() string { return faker.URL() })
 }
 
 func (s *SecureRandomSource) DomainName() string {
-	return faker.DomainName()
+	return callFaker(s.seeded, func() string { return faker.DomainName() })
 }
 
 func (s *SecureRandomSource) IPv4() string {
-	return faker.IPv4()
+	return callFaker(s.seeded, func() string { return faker.IPv4() })
 }
 
 func (s *SecureRandomSource) IPv6() string {
-	return faker.IPv6()
+	return callFaker(s.seeded, func() string { return faker.IPv6() })
+}
+
+func (s *SecureRandomSource) Address() Address {
+	fake := callFaker(s.seeded, func() faker.RealAddress { return faker.GetRealAddress() })
+	return Address{
+		Street:      fake.Address,
+		City:        fake.City,
+		State:       fake.State,
+		PostalCode:  fake.PostalCode,
+		Country:     "United States", // faker's real-world addresses are all in the US
+		CountryCode: "US",
+		Latitude:    fake.Coordinates.Latitude,
+		Longitude:   fake.Coordinates.Longitude,
+	}
 }
 
 func (s *SecureRandomSource) GeneratePattern(pattern string, maxLength int) (string, error) {
@@ -133,3 +191,53 @@ func (s *SecureRandomSource) DateTime() string {
 	datetime := time.Now().AddDate(0, 0, days-182) // ±6 months from now
 	return datetime.Format(time.RFC3339)
 }
+
+// seededEpoch anchors generated dates for seeded sources, so that their output
+// does not change from one day to the next
+var seededEpoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SeededRandomSource implements RandomSource with a reproducible sequence.
+// Faker values are derived from the seed one call at a time, so other sources
+// in the process are unaffected.
+type SeededRandomSource struct {
+	SecureRandomSource
+	rand *mathrand.Rand
+}
+
+// NewSeededRandomSource creates a random source that repeats its output for the same seed
+func NewSeededRandomSource(seed int64) *SeededRandomSource {
+	r := mathrand.New(mathrand.NewSource(seed)) // #nosec G404 - reproducible mock data, not security
+	return &SeededRandomSource{SecureRandomSource: SecureRandomSource{seeded: r}, rand: r}
+}
+
+func (s *SeededRandomSource) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return s.rand.Intn(n)
+}
+
+func (s *SeededRandomSource) Float64() float64 {
+	return s.rand.Float64()
+}
+
+func (s *SeededRandomSource) Int() int {
+	return s.rand.Int()
+}
+
+func (s *SeededRandomSource) GeneratePattern(pattern string, maxLength int) (string, error) {
+	gen, err := reggen.NewGenerator(pattern)
+	if err != nil {
+		return "", err
+	}
+	gen.SetSeed(s.rand.Int63())
+	return gen.Generate(maxLength), nil
+}
+
+func (s *SeededRandomSource) Date() string {
+	return seededEpoch.AddDate(0, 0, s.Intn(365)-182).Format("2006-01-02")
+}
+
+func (s *SeededRandomSource) DateTime() string {
+	return seededEpoch.AddDate(0, 0, s.Intn(365)-182).Format(time.RFC3339)
+}
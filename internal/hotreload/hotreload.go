@@ -36,6 +36,11 @@ func (m *Manager) AddWatch(path string) error {
 	return m.watcher.Add(path)
 }
 
+// AddWatchDir watches a directory tree for changes to spec files
+func (m *Manager) AddWatchDir(dir string) error {
+	return m.watcher.AddSpecDir(dir)
+}
+
 // RemoveWatch removes a file or directory from watch
 func (m *Manager) RemoveWatch(path string) error {
 	return m.watcher.Remove(path)
@@ -3,8 +3,10 @@ package hotreload
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
@@ -14,6 +16,7 @@ import (
 type Watcher struct {
 	watcher    *fsnotify.Watcher
 	paths      []string
+	specDirs   map[string]bool // Directories where only spec files trigger events
 	events     chan Event
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -40,6 +43,7 @@ func NewWatcher() (*Watcher, error) {
 	return &Watcher{
 		watcher:    fsWatcher,
 		paths:      make([]string, 0),
+		specDirs:   make(map[string]bool),
 		events:     make(chan Event, 100),
 		ctx:        ctx,
 		cancel:     cancel,
@@ -66,6 +70,35 @@ func (w *Watcher) Add(path string) error {
 	return nil
 }
 
+// AddSpecDir watches a directory and its subdirectories for changes to spec
+// files (.yaml, .yml, .json, and gzipped .gz), such as files referenced with $ref. Other
+// files and hidden directories are ignored, as are subdirectories created later.
+func (w *Watcher) AddSpecDir(dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	return filepath.WalkDir(absDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != absDir && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := w.Add(path); err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.specDirs[path] = true
+		w.mu.Unlock()
+		return nil
+	})
+}
+
 // Remove removes a file or directory from watch
 func (w *Watcher) Remove(path string) error {
 	w.mu.Lock()
@@ -174,6 +207,18 @@ func (w *Watcher) shouldSkipEvent(path string) bool {
 		filepath.Base(path)[0] == '~' {
 		return true
 	}
+
+	// Spec directories only report changes to spec files
+	w.mu.RLock()
+	specDir := w.specDirs[filepath.Dir(path)]
+	w.mu.RUnlock()
+	if specDir {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json", ".gz":
+		default:
+			return true
+		}
+	}
 	return false
 }
 
@@ -204,3 +204,49 @@ func TestWatcher_shouldSkipEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestWatcher_AddSpecDir(t *testing.T) {
+	w, err := NewWatcher()
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+	defer w.Stop()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "schemas"), 0755); err != nil {
+		t.Fatalf("Mkdir() failed: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatalf("Mkdir() failed: %v", err)
+	}
+	if err := w.AddSpecDir(dir); err != nil {
+		t.Fatalf("AddSpecDir() failed: %v", err)
+	}
+
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{filepath.Join(dir, "openapi.yaml"), false},
+		{filepath.Join(dir, "openapi.YML"), false},
+		{filepath.Join(dir, "schemas", "pet.json"), false},
+		{filepath.Join(dir, "openapi.yaml.gz"), false},
+		{filepath.Join(dir, "notes.txt"), true},
+		{filepath.Join(dir, "schemas", "pet.yaml.swp"), true},
+	}
+
+	w.mu.RLock()
+	hiddenWatched := w.specDirs[filepath.Join(dir, ".git")]
+	w.mu.RUnlock()
+	if hiddenWatched {
+		t.Error("AddSpecDir() watched a hidden directory")
+	}
+
+	for _, tc := range testCases {
+		t.Run(filepath.Base(tc.path), func(t *testing.T) {
+			if got := w.shouldSkipEvent(tc.path); got != tc.expected {
+				t.Errorf("shouldSkipEvent(%q) = %v; want %v", tc.path, got, tc.expected)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ type HealthStatus struct {
 	Status    string                 `json:"status"`
 	Timestamp time.Time              `json:"timestamp"`
 	Version   string                 `json:"version"`
+	Commit    string                 `json:"commit"`
+	BuildDate string                 `json:"build_date"`
 	Uptime    string                 `json:"uptime"`
 	Metrics   map[string]interface{} `json:"metrics,omitempty"`
 	Checks    map[string]bool        `json:"checks"`
@@ -0,0 +1,45 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+)
+
+// tracerName identifies the instrumentation library on exported spans
+const tracerName = "github.com/leslieo2/go-spec-mock"
+
+type Tracer struct {
+	trace.Tracer
+	provider *sdktrace.TracerProvider
+}
+
+// NewTracer creates a tracer that batches spans and exports them over OTLP/HTTP
+func NewTracer(config config.TracingConfig) (*Tracer, error) {
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(config.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return newTracerWithExporter(config, exporter), nil
+}
+
+// newTracerWithExporter creates a tracer that sends spans to the given exporter
+func newTracerWithExporter(config config.TracingConfig, exporter sdktrace.SpanExporter) *Tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName))),
+	)
+	return &Tracer{Tracer: provider.Tracer(tracerName), provider: provider}
+}
+
+// Shutdown flushes pending spans and stops the exporter
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
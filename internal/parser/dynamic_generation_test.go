@@ -524,6 +524,17 @@ func testArrayLengthVariation(t *testing.T) {
 			},
 			description: "should generate 1-3 items when no constraints specified",
 		},
+		{
+			name: "enum_schema",
+			schema: &openapi3.Schema{
+				Type: &openapi3.Types{"string"},
+				Enum: []interface{}{"option1", "option2", "option3"},
+			},
+			validator: func(v interface{}) bool {
+				return v == "option1" || v == "option2" || v == "option3"
+			},
+			description: "should return one of the enum values",
+		},
 	}
 
 	for _, tt := range tests {
@@ -553,15 +564,6 @@ func testBackwardCompatibility(t *testing.T) {
 			expected:    "explicit_example_value",
 			description: "should return explicit example when provided",
 		},
-		{
-			name: "enum_schema",
-			schema: &openapi3.Schema{
-				Type: &openapi3.Types{"string"},
-				Enum: []interface{}{"option1", "option2", "option3"},
-			},
-			expected:    "option1", // Current implementation returns first enum value
-			description: "should return enum value (current behavior)",
-		},
 		{
 			name:        "null_schema",
 			schema:      nil,
@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// originMu guards openapi3.IncludeOrigin, the package variable kin-openapi reads
+// to decide whether to record source positions. Origin-tracking loads hold it
+// exclusively; every other load holds it for reading, so it never sees the
+// flag set and leaks __origin__ keys into example values.
+var originMu sync.RWMutex
+
+// loadPropertyOrder loads spec a second time with source positions and returns
+// the declared property order of every response schema in doc. Positions are
+// not kept in doc itself, because they also appear as __origin__ keys inside
+// example values.
+func loadPropertyOrder(doc *openapi3.T, spec []byte) (map[*openapi3.Schema][]string, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	originMu.Lock()
+	openapi3.IncludeOrigin = true
+	located, err := loader.LoadFromData(spec)
+	openapi3.IncludeOrigin = false
+	originMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record property order: %w", err)
+	}
+
+	w := &orderWalker{order: make(map[*openapi3.Schema][]string)}
+	if doc.Components != nil && located.Components != nil {
+		for name, schema := range doc.Components.Schemas {
+			w.schema(schema, located.Components.Schemas[name])
+		}
+		for name, response := range doc.Components.Responses {
+			w.response(response, located.Components.Responses[name])
+		}
+	}
+	if doc.Paths != nil && located.Paths != nil {
+		for path, pathItem := range doc.Paths.Map() {
+			locatedItem := located.Paths.Value(path)
+			if locatedItem == nil {
+				continue
+			}
+			for method, operation := range pathItem.Operations() {
+				locatedOperation := locatedItem.GetOperation(method)
+				if operation.Responses == nil || locatedOperation == nil || locatedOperation.Responses == nil {
+					continue
+				}
+				for code, response := range operation.Responses.Map() {
+					w.response(response, locatedOperation.Responses.Value(code))
+				}
+			}
+		}
+	}
+	return w.order, nil
+}
+
+// orderWalker walks a spec alongside its origin-tracking copy, recording the
+// declared property order of each schema
+type orderWalker struct {
+	order map[*openapi3.Schema][]string
+}
+
+func (w *orderWalker) response(response, located *openapi3.ResponseRef) {
+	if response == nil || response.Value == nil || located == nil || located.Value == nil {
+		return
+	}
+	for name, mediaType := range response.Value.Content {
+		if locatedType := located.Value.Content[name]; mediaType != nil && locatedType != nil {
+			w.schema(mediaType.Schema, locatedType.Schema)
+		}
+	}
+}
+
+func (w *orderWalker) schema(ref, located *openapi3.SchemaRef) {
+	if ref == nil || ref.Value == nil || located == nil || located.Value == nil {
+		return
+	}
+	schema, locatedSchema := ref.Value, located.Value
+	if _, seen := w.order[schema]; seen {
+		return
+	}
+
+	names := make([]string, 0, len(locatedSchema.Properties))
+	for name := range locatedSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		li := declaredAt(locatedSchema.Properties[names[i]])
+		lj := declaredAt(locatedSchema.Properties[names[j]])
+		if li.Line != lj.Line {
+			return li.Line < lj.Line
+		}
+		if li.Column != lj.Column {
+			return li.Column < lj.Column
+		}
+		return names[i] < names[j]
+	})
+	w.order[schema] = names
+
+	for name, prop := range schema.Properties {
+		w.schema(prop, locatedSchema.Properties[name])
+	}
+	w.schema(schema.Items, locatedSchema.Items)
+	w.schema(schema.AdditionalProperties.Schema, locatedSchema.AdditionalProperties.Schema)
+	w.schemas(schema.AllOf, locatedSchema.AllOf)
+	w.schemas(schema.OneOf, locatedSchema.OneOf)
+	w.schemas(schema.AnyOf, locatedSchema.AnyOf)
+}
+
+func (w *orderWalker) schemas(refs, located openapi3.SchemaRefs) {
+	if len(refs) != len(located) {
+		return
+	}
+	for i := range refs {
+		w.schema(refs[i], located[i])
+	}
+}
+
+// declaredAt returns where a property's key appears in the spec. For a $ref
+// property that is the reference, not the referenced component.
+func declaredAt(prop *openapi3.SchemaRef) openapi3.Location {
+	origin := prop.Origin
+	if prop.Ref == "" && prop.Value != nil {
+		origin = prop.Value.Origin
+	}
+	if origin == nil || origin.Key == nil {
+		return openapi3.Location{}
+	}
+	return *origin.Key
+}
+
+// orderedObject is a JSON object whose fields are written in slice order
+type orderedObject []orderedField
+
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalJSON writes the fields in order rather than sorted by key
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// OrderProperties returns value with its objects arranged so that they marshal
+// with properties in the order schema declares them, including properties from
+// allOf, oneOf, and anyOf subschemas in turn. Undeclared keys follow in
+// alphabetical order. Declaration order is only known for specs loaded with
+// Options.PropertyOrder; otherwise declared properties are also alphabetical.
+func (p *Parser) OrderProperties(value interface{}, schema *openapi3.Schema) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var names []string
+		properties := make(map[string]*openapi3.Schema)
+		p.collectProperties(schema, &names, properties, make(map[*openapi3.Schema]bool))
+
+		keys := make([]string, 0, len(v))
+		for _, name := range names {
+			if _, ok := v[name]; ok {
+				keys = append(keys, name)
+			}
+		}
+		declared := len(keys)
+		for key := range v {
+			if _, ok := properties[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys[declared:])
+
+		object := make(orderedObject, 0, len(keys))
+		for _, key := range keys {
+			propSchema, ok := properties[key]
+			if !ok && schema != nil && schema.AdditionalProperties.Schema != nil {
+				propSchema = schema.AdditionalProperties.Schema.Value
+			}
+			object = append(object, orderedField{Key: key, Value: p.OrderProperties(v[key], propSchema)})
+		}
+		return object
+	case []interface{}:
+		var itemSchema *openapi3.Schema
+		if schema != nil && schema.Items != nil {
+			itemSchema = schema.Items.Value
+		}
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = p.OrderProperties(item, itemSchema)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// collectProperties appends the property names of schema and its subschemas to
+// names in declared order, keeping the first schema given for each name
+func (p *Parser) collectProperties(schema *openapi3.Schema, names *[]string, properties map[string]*openapi3.Schema, seen map[*openapi3.Schema]bool) {
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+
+	order, ok := p.propertyOrder[schema]
+	if !ok {
+		order = make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			order = append(order, name)
+		}
+		sort.Strings(order)
+	}
+	for _, name := range order {
+		prop := schema.Properties[name]
+		if _, exists := properties[name]; exists || prop == nil {
+			continue
+		}
+		*names = append(*names, name)
+		properties[name] = prop.Value
+	}
+
+	for _, group := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, sub := range group {
+			if sub != nil {
+				p.collectProperties(sub.Value, names, properties, seen)
+			}
+		}
+	}
+}
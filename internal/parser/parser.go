@@ -1,43 +1,195 @@
 package parser
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/leslieo2/go-spec-mock/internal/constants"
 	"github.com/leslieo2/go-spec-mock/internal/generator"
 )
 
+// ErrNoContent is returned for responses that define no content, such as a 204
+var ErrNoContent = errors.New("no content defined")
+
+// exampleCacheKey identifies a cached example. Operations are keyed by pointer,
+// since operationId is optional.
+type exampleCacheKey struct {
+	operation   *openapi3.Operation
+	statusCode  string
+	exampleName string
+}
+
 type Parser struct {
-	doc   *openapi3.T
-	cache *sync.Map // Cache for pre-generated examples
+	doc             *openapi3.T
+	raw             []byte    // Spec file contents as read from disk or stdin
+	cache           *sync.Map // Cache for pre-generated examples
+	generatorConfig generator.Config
+
+	// propertyOrder holds the declared property order of each schema, when
+	// loaded with Options.PropertyOrder
+	propertyOrder map[*openapi3.Schema][]string
+}
+
+// readStdin reads the spec from standard input once; later loads, such as
+// reloads, reuse the buffered contents
+var readStdin = sync.OnceValues(func() ([]byte, error) {
+	return io.ReadAll(os.Stdin)
+})
+
+// Options controls how the spec file is loaded
+type Options struct {
+	// ExpandEnv replaces ${VAR} tokens with environment variables before parsing
+	ExpandEnv bool
+	// StrictEnv fails the load when an expanded variable is not set, instead
+	// of expanding it to an empty string
+	StrictEnv bool
+	// PropertyOrder records the order schema properties are declared in, so
+	// that OrderProperties can follow it
+	PropertyOrder bool
 }
 
+// envTokenPattern matches ${VAR} tokens; bare $name, such as $ref, is left alone
+var envTokenPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// New parses the spec at specPath, or from standard input when specPath is "-"
 func New(specPath string) (*Parser, error) {
+	return NewWithOptions(specPath, Options{})
+}
+
+// NewWithOptions parses the spec at specPath like New, applying opts
+func NewWithOptions(specPath string, opts Options) (*Parser, error) {
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
 
-	cleanedPath := filepath.Clean(specPath)
-
-	data, err := os.ReadFile(cleanedPath)
+	var data []byte
+	var err error
+	if specPath == constants.SpecFileStdin {
+		data, err = readStdin()
+	} else {
+		data, err = os.ReadFile(filepath.Clean(specPath))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read spec file: %w", err)
 	}
+	if data, err = decompress(specPath, data); err != nil {
+		return nil, err
+	}
+
+	spec := data
+	if opts.ExpandEnv {
+		if spec, err = expandEnv(data, opts.StrictEnv); err != nil {
+			return nil, err
+		}
+	}
 
-	doc, err := loader.LoadFromData(data)
+	originMu.RLock()
+	doc, err := loader.LoadFromData(spec)
+	originMu.RUnlock()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
 	}
 
+	normalizeNullTypes(doc)
+
 	if err := doc.Validate(loader.Context); err != nil {
 		return nil, fmt.Errorf("OpenAPI spec validation failed: %w", err)
 	}
 
-	return &Parser{doc: doc, cache: &sync.Map{}}, nil
+	p := &Parser{doc: doc, raw: data, cache: &sync.Map{}, generatorConfig: defaultGeneratorConfig()}
+	if opts.PropertyOrder {
+		if p.propertyOrder, err = loadPropertyOrder(doc, spec); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// gzipMagic prefixes gzip-compressed data
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompress returns the spec uncompressed when it is gzipped, as marked by a
+// .gz extension or the gzip magic bytes, and unchanged otherwise
+func decompress(specPath string, data []byte) ([]byte, error) {
+	if !strings.EqualFold(filepath.Ext(specPath), ".gz") && !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress spec file: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	decompressed, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress spec file: %w", err)
+	}
+	return decompressed, nil
+}
+
+// expandEnv replaces ${VAR} tokens in data with their environment values.
+// Unset variables expand to an empty string, or are reported when strict.
+func expandEnv(data []byte, strict bool) ([]byte, error) {
+	var missing []string
+	expanded := envTokenPattern.ReplaceAllFunc(data, func(token []byte) []byte {
+		name := string(token[2 : len(token)-1])
+		value, ok := os.LookupEnv(name)
+		if !ok && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+		return []byte(value)
+	})
+	if strict && len(missing) > 0 {
+		return nil, fmt.Errorf("undefined environment variables in spec: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// RawSpec returns the spec file exactly as it was read, comments and formatting
+// included, after decompressing a gzipped spec
+func (p *Parser) RawSpec() []byte {
+	return p.raw
+}
+
+// Document returns the parsed spec
+func (p *Parser) Document() *openapi3.T {
+	return p.doc
+}
+
+// SetGeneratorConfig sets the configuration used for schema-based generation
+// and discards any examples generated with the previous configuration
+func (p *Parser) SetGeneratorConfig(config generator.Config) {
+	p.generatorConfig = config
+	p.cache = &sync.Map{}
+}
+
+// GeneratorConfig returns the configuration used for schema-based generation
+func (p *Parser) GeneratorConfig() generator.Config {
+	return p.generatorConfig
+}
+
+// defaultGeneratorConfig returns the generator configuration used when none is set
+func defaultGeneratorConfig() generator.Config {
+	return generator.Config{
+		UseFieldNameForData: true, // Enable field name intelligence
+		DefaultArrayLength:  2,    // Generate 2 items by default
+	}
 }
 
 func (p *Parser) GetRoutes() []Route {
@@ -61,6 +213,47 @@ func (p *Parser) GetRoutes() []Route {
 	return routes
 }
 
+// ServerBasePaths returns the distinct path components of the spec's servers,
+// in declaration order and without a trailing slash. A server at the root, or
+// with a URL that cannot be parsed, contributes "".
+func (p *Parser) ServerBasePaths() []string {
+	seen := make(map[string]bool, len(p.doc.Servers))
+	basePaths := make([]string, 0, len(p.doc.Servers))
+	for _, server := range p.doc.Servers {
+		basePath, err := server.BasePath()
+		if err != nil {
+			basePath = ""
+		}
+		basePath = strings.TrimRight(basePath, "/")
+		if !seen[basePath] {
+			seen[basePath] = true
+			basePaths = append(basePaths, basePath)
+		}
+	}
+	return basePaths
+}
+
+// FindOperation looks up an operation by operationId or by "METHOD /path"
+func (p *Parser) FindOperation(name string) (Route, bool) {
+	name = strings.TrimSpace(name)
+	wantMethod, wantPath, byPath := strings.Cut(name, " ")
+	wantMethod = strings.ToUpper(wantMethod)
+	wantPath = strings.TrimSpace(wantPath)
+
+	for path, pathItem := range p.doc.Paths.Map() {
+		for method := range methodMap {
+			operation := pathItem.GetOperation(method)
+			if operation == nil {
+				continue
+			}
+			if (byPath && method == wantMethod && path == wantPath) || (!byPath && operation.OperationID == name) {
+				return Route{Path: path, Method: method, Operation: operation}, true
+			}
+		}
+	}
+	return Route{}, false
+}
+
 func (p *Parser) preGenerateExamples(operation *openapi3.Operation) {
 	if operation == nil || operation.Responses == nil {
 		return
@@ -85,33 +278,115 @@ func (p *Parser) preGenerateExamples(operation *openapi3.Operation) {
 	}
 }
 
+// Warmup builds and caches the example of every response of every operation,
+// the default one as well as each named example, and returns how many were
+// cached. Responses without content are skipped.
+func (p *Parser) Warmup() int {
+	entries := 0
+	for _, pathItem := range p.doc.Paths.Map() {
+		for method := range methodMap {
+			operation := pathItem.GetOperation(method)
+			if operation == nil || operation.Responses == nil {
+				continue
+			}
+			for code := range operation.Responses.Map() {
+				for _, name := range append([]string{""}, p.ExampleNames(operation, code)...) {
+					if _, err := p.GetExampleResponse(operation, code, name); err == nil {
+						entries++
+					}
+				}
+			}
+		}
+	}
+	return entries
+}
+
 func (p *Parser) GetExampleResponse(operation *openapi3.Operation, statusCode string, exampleName string) (interface{}, error) {
 	if operation.Responses == nil {
 		return nil, fmt.Errorf("no responses defined")
 	}
 
 	// Check cache first
-	cacheKey := operation.OperationID + ":" + statusCode
-	if exampleName != "" {
-		cacheKey += ":" + exampleName
-	}
+	cacheKey := exampleCacheKey{operation: operation, statusCode: statusCode, exampleName: exampleName}
 	if cached, ok := p.cache.Load(cacheKey); ok {
 		return cached, nil
 	}
 
-	response, exists := operation.Responses.Map()[statusCode]
-	if !exists || response == nil || response.Value == nil {
-		return nil, fmt.Errorf("response %s not found", statusCode)
+	result, err := p.buildExample(operation, statusCode, exampleName, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	content := response.Value.Content
-	if content == nil {
-		return nil, fmt.Errorf("no content defined for response %s", statusCode)
+	// Cache the result
+	p.cache.Store(cacheKey, result)
+	return result, nil
+}
+
+// GenerateExampleResponse builds a response example like GetExampleResponse but
+// bypasses the example cache, so schema-based data is generated afresh
+func (p *Parser) GenerateExampleResponse(operation *openapi3.Operation, statusCode string, exampleName string) (interface{}, error) {
+	if operation.Responses == nil {
+		return nil, fmt.Errorf("no responses defined")
+	}
+	return p.buildExample(operation, statusCode, exampleName, nil)
+}
+
+// GenerateExampleResponseForPath builds a fresh response example like
+// GenerateExampleResponse, filling generated properties named after a path
+// parameter with the value from the request path
+func (p *Parser) GenerateExampleResponseForPath(operation *openapi3.Operation, statusCode string, exampleName string, pathParams map[string]string) (interface{}, error) {
+	if operation.Responses == nil {
+		return nil, fmt.Errorf("no responses defined")
+	}
+	return p.buildExample(operation, statusCode, exampleName, pathParams)
+}
+
+// FillsPathParams reports whether data generated for any response of operation
+// takes a value from params, because a top-level property is named after one of
+// them. Other responses do not depend on the path and can be cached.
+func FillsPathParams(operation *openapi3.Operation, params map[string]string) bool {
+	if operation.Responses == nil {
+		return false
+	}
+	for code := range operation.Responses.Map() {
+		_, mediaType, err := ResponseContent(operation, code)
+		if err != nil || mediaType.Schema == nil {
+			continue
+		}
+		if hasPropertyNamed(mediaType.Schema.Value, params) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPropertyNamed reports whether schema, or a schema it composes, declares a
+// property with one of the names in params
+func hasPropertyNamed(schema *openapi3.Schema, params map[string]string) bool {
+	if schema == nil {
+		return false
+	}
+	for name := range schema.Properties {
+		if _, ok := params[name]; ok {
+			return true
+		}
+	}
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, ref := range refs {
+			if ref != nil && hasPropertyNamed(ref.Value, params) {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	jsonContent := content.Get(constants.ContentTypeJSON)
-	if jsonContent == nil {
-		return nil, fmt.Errorf("no application/json content defined")
+// buildExample resolves the named example, the default example, or data generated
+// from the schema for a response
+func (p *Parser) buildExample(operation *openapi3.Operation, statusCode string, exampleName string, pathParams map[string]string) (interface{}, error) {
+	_, jsonContent, err := ResponseContent(operation, statusCode)
+	if err != nil {
+		return nil, err
 	}
 
 	var result interface{}
@@ -125,7 +400,7 @@ func (p *Parser) GetExampleResponse(operation *openapi3.Operation, statusCode st
 			if jsonContent.Example != nil {
 				result = jsonContent.Example
 			} else if schema := jsonContent.Schema; schema != nil && schema.Value != nil {
-				result = generateExampleFromSchema(schema.Value)
+				result = p.generate(schema.Value, pathParams)
 			} else {
 				return nil, fmt.Errorf("named example '%s' not found and no fallback available", exampleName)
 			}
@@ -134,39 +409,272 @@ func (p *Parser) GetExampleResponse(operation *openapi3.Operation, statusCode st
 		// Use single example field
 		result = jsonContent.Example
 	} else if len(jsonContent.Examples) > 0 {
-		// If no specific example requested but examples exist, use the first one
-		for _, example := range jsonContent.Examples {
-			if example != nil && example.Value != nil {
-				result = example.Value.Value
-				break
-			}
+		// If no specific example requested but examples exist, use the first one by name
+		if names := sortedExampleNames(jsonContent); len(names) > 0 {
+			result = jsonContent.Examples[names[0]].Value.Value
 		}
 		if result == nil {
 			// No valid examples found, generate from schema
 			if schema := jsonContent.Schema; schema != nil && schema.Value != nil {
-				result = generateExampleFromSchema(schema.Value)
+				result = p.generate(schema.Value, pathParams)
 			} else {
 				return nil, fmt.Errorf("no valid examples or schema found")
 			}
 		}
 	} else if schema := jsonContent.Schema; schema != nil && schema.Value != nil {
 		// Generate from schema
-		result = generateExampleFromSchema(schema.Value)
+		result = p.generate(schema.Value, pathParams)
 	} else {
-		return nil, fmt.Errorf("no example or schema found")
+		// JSON content without example or schema, common in loosely-specified specs
+		result = map[string]interface{}{}
 	}
 
-	// Cache the result
-	p.cache.Store(cacheKey, result)
 	return result, nil
 }
 
-func generateExampleFromSchema(schema *openapi3.Schema) interface{} {
-	// Create a generator for consistent results
-	config := generator.Config{
-		UseFieldNameForData: true, // Enable field name intelligence
-		DefaultArrayLength:  2,    // Generate 2 items by default
+// ExampleNames returns the sorted names of the examples defined for a response
+func (p *Parser) ExampleNames(operation *openapi3.Operation, statusCode string) []string {
+	_, mediaType, err := ResponseContent(operation, statusCode)
+	if err != nil {
+		return nil
+	}
+	return sortedExampleNames(mediaType)
+}
+
+// ResponseContent returns the media type used to mock the given response and its
+// definition. application/json is preferred, then other JSON types, then
+// multipart/form-data.
+func ResponseContent(operation *openapi3.Operation, statusCode string) (string, *openapi3.MediaType, error) {
+	if operation.Responses == nil {
+		return "", nil, fmt.Errorf("no responses defined")
+	}
+
+	response, exists := operation.Responses.Map()[statusCode]
+	if !exists || response == nil || response.Value == nil {
+		return "", nil, fmt.Errorf("response %s not found", statusCode)
+	}
+
+	content := response.Value.Content
+	if len(content) == 0 {
+		return "", nil, fmt.Errorf("%w for response %s", ErrNoContent, statusCode)
+	}
+
+	if mediaType := content.Get(constants.ContentTypeJSON); mediaType != nil {
+		return constants.ContentTypeJSON, mediaType, nil
+	}
+
+	// JSON-based types such as application/vnd.api+json, in sorted order for determinism
+	names := make([]string, 0, len(content))
+	for name := range content {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if isJSONMediaType(name) && content[name] != nil {
+			return name, content[name], nil
+		}
+	}
+
+	if mediaType := content.Get(constants.ContentTypeMultipartFormData); mediaType != nil {
+		return constants.ContentTypeMultipartFormData, mediaType, nil
+	}
+	for _, name := range names {
+		if IsBinaryMediaType(name, content[name]) {
+			return name, content[name], nil
+		}
+	}
+	return "", nil, fmt.Errorf("no JSON, multipart/form-data, or binary content defined")
+}
+
+// binaryMediaTypePrefixes are media types whose bodies are raw bytes
+var binaryMediaTypePrefixes = []string{"image/", "audio/", "video/", "application/pdf", "application/zip", "application/octet-stream"}
+
+// IsBinaryMediaType reports whether a response body is raw bytes: the media type
+// is an image, audio, video, PDF, zip, or octet-stream one, or its schema is a
+// string with format binary
+func IsBinaryMediaType(name string, mediaType *openapi3.MediaType) bool {
+	if mediaType == nil {
+		return false
+	}
+	for _, prefix := range binaryMediaTypePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return mediaType.Schema != nil && mediaType.Schema.Value != nil && mediaType.Schema.Value.Format == "binary"
+}
+
+// isJSONMediaType reports whether a media type is application/json or uses the +json suffix
+func isJSONMediaType(name string) bool {
+	base, _, err := mime.ParseMediaType(name)
+	if err != nil {
+		return false
+	}
+	return base == constants.ContentTypeJSON || strings.HasSuffix(base, "+json")
+}
+
+// sortedExampleNames returns the names of the usable named examples in sorted order
+func sortedExampleNames(mediaType *openapi3.MediaType) []string {
+	names := make([]string, 0, len(mediaType.Examples))
+	for name, example := range mediaType.Examples {
+		if example != nil && example.Value != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExampleNameForVersion returns the named example mapped to the given API version
+// by the operation's x-mock-versions extension, or an empty string if none is mapped
+func ExampleNameForVersion(operation *openapi3.Operation, version string) string {
+	if operation == nil || version == "" {
+		return ""
+	}
+
+	versions, ok := operation.Extensions[constants.ExtensionMockVersions].(map[string]interface{})
+	if !ok {
+		return ""
 	}
+
+	exampleName, _ := versions[version].(string)
+	return exampleName
+}
+
+// ExampleNameForQuery returns the named example mapped to the request's query
+// parameters by the operation's x-mock-query-examples extension, or an empty
+// string if none is mapped. Only parameters the operation declares with
+// in: query are consulted, in alphabetical order.
+func ExampleNameForQuery(operation *openapi3.Operation, query url.Values) string {
+	if operation == nil || len(query) == 0 {
+		return ""
+	}
+
+	mappings, ok := operation.Extensions[constants.ExtensionMockQueryExamples].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	names := make([]string, 0, len(mappings))
+	for name := range mappings {
+		if param := operation.Parameters.GetByInAndName(openapi3.ParameterInQuery, name); param != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		examples, ok := mappings[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if exampleName, _ := examples[query.Get(name)].(string); exampleName != "" {
+			return exampleName
+		}
+	}
+	return ""
+}
+
+// OperationCaching returns the operation's x-mock-cache setting, reporting
+// false when the extension is absent or not a boolean
+func OperationCaching(operation *openapi3.Operation) (enabled bool, ok bool) {
+	if operation == nil {
+		return false, false
+	}
+	enabled, ok = operation.Extensions[constants.ExtensionMockCache].(bool)
+	return enabled, ok
+}
+
+// OperationRotation returns the operation's x-mock-rotation strategy, or an
+// empty string when the extension is absent or not a string
+func OperationRotation(operation *openapi3.Operation) string {
+	if operation == nil {
+		return ""
+	}
+	strategy, _ := operation.Extensions[constants.ExtensionMockRotation].(string)
+	return strings.TrimSpace(strategy)
+}
+
+// OperationUnimplemented reports whether the operation is marked with
+// x-mock-unimplemented: true, so it is served as 501 Not Implemented
+func OperationUnimplemented(operation *openapi3.Operation) bool {
+	if operation == nil {
+		return false
+	}
+	unimplemented, _ := operation.Extensions[constants.ExtensionMockUnimplemented].(bool)
+	return unimplemented
+}
+
+// OperationDelay returns the latency declared by the operation's x-mock-delay
+// extension, either a duration such as "300ms" or a number of milliseconds.
+// It returns zero when the extension is absent, and caps the delay at
+// constants.MaxDelayDuration.
+func OperationDelay(operation *openapi3.Operation) (time.Duration, error) {
+	if operation == nil {
+		return 0, nil
+	}
+
+	var delay time.Duration
+	switch value := operation.Extensions[constants.ExtensionMockDelay].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		delay = time.Duration(value * float64(time.Millisecond))
+	case string:
+		if ms, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			delay = time.Duration(ms) * time.Millisecond
+		} else if delay, err = time.ParseDuration(strings.TrimSpace(value)); err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", constants.ExtensionMockDelay, value, err)
+		}
+	default:
+		return 0, fmt.Errorf("invalid %s %v: expected a duration or milliseconds", constants.ExtensionMockDelay, value)
+	}
+
+	if delay < 0 {
+		return 0, fmt.Errorf("invalid %s: delay must not be negative", constants.ExtensionMockDelay)
+	}
+	if delay > constants.MaxDelayDuration {
+		return constants.MaxDelayDuration, nil
+	}
+	return delay, nil
+}
+
+// ResponseTrailers returns the HTTP trailers declared by the response's
+// x-mock-trailers extension, or nil if it declares none
+func ResponseTrailers(operation *openapi3.Operation, statusCode string) map[string]string {
+	if operation == nil || operation.Responses == nil {
+		return nil
+	}
+
+	response := operation.Responses.Map()[statusCode]
+	if response == nil || response.Value == nil {
+		return nil
+	}
+
+	declared, ok := response.Value.Extensions[constants.ExtensionMockTrailers].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	trailers := make(map[string]string, len(declared))
+	for name, value := range declared {
+		trailers[http.CanonicalHeaderKey(name)] = fmt.Sprint(value)
+	}
+	return trailers
+}
+
+// generate builds data from a schema with the parser's generator configuration
+func (p *Parser) generate(schema *openapi3.Schema, pathParams map[string]string) interface{} {
+	gen := generator.New(p.generatorConfig)
+
+	return gen.GenerateDataWithContext(schema, generator.GenerationContext{PathParams: pathParams})
+}
+
+func generateExampleFromSchema(schema *openapi3.Schema) interface{} {
+	return generateExampleWithConfig(schema, defaultGeneratorConfig())
+}
+
+func generateExampleWithConfig(schema *openapi3.Schema, config generator.Config) interface{} {
 	gen := generator.New(config)
 
 	return gen.GenerateData(schema)
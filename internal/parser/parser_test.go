@@ -1,7 +1,19 @@
 package parser
 
 import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
 )
 
 func TestNew(t *testing.T) {
@@ -114,3 +126,439 @@ func TestGetExampleResponse_ValidCodes(t *testing.T) {
 		}
 	}
 }
+
+func TestGetExampleResponse_TypeLists(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Counters API
+  version: 1.0.0
+paths:
+  /counters:
+    get:
+      operationId: getCounter
+      responses:
+        "200":
+          description: A counter
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  count:
+                    type: [integer, "null"]
+                  label:
+                    type: ["null", string]
+                  city:
+                    type: [string, "null"]
+`
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	parser, err := New(specPath)
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	routes := parser.GetRoutes()
+	if len(routes) != 1 {
+		t.Fatalf("Expected 1 route, got %d", len(routes))
+	}
+
+	example, err := parser.GenerateExampleResponse(routes[0].Operation, "200", "")
+	if err != nil {
+		t.Fatalf("Failed to generate example: %v", err)
+	}
+	body, ok := example.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected an object, got %T", example)
+	}
+
+	// Null is never generated by default, so the first non-null type is used
+	if _, ok := body["count"].(int); !ok {
+		t.Errorf("Expected count to be an integer, got %#v", body["count"])
+	}
+	for _, name := range []string{"label", "city"} {
+		if value, ok := body[name].(string); !ok || value == "" {
+			t.Errorf("Expected %s to be a non-empty string, got %#v", name, body[name])
+		}
+	}
+}
+
+func TestNewWithOptions_ExpandEnv(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Env API
+  version: 1.0.0
+servers:
+  - url: ${TEST_BASE_URL}/v1
+paths:
+  /greeting:
+    get:
+      responses:
+        "200":
+          description: A greeting
+          content:
+            application/json:
+              example:
+                message: ${TEST_GREETING}
+`
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	t.Setenv("TEST_BASE_URL", "https://staging.example.com")
+	t.Setenv("TEST_GREETING", "hello")
+
+	parser, err := NewWithOptions(specPath, Options{ExpandEnv: true})
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	if url := parser.doc.Servers[0].URL; url != "https://staging.example.com/v1" {
+		t.Errorf("Expected expanded server URL, got %q", url)
+	}
+	example, err := parser.GetExampleResponse(parser.GetRoutes()[0].Operation, "200", "")
+	if err != nil {
+		t.Fatalf("Failed to get example: %v", err)
+	}
+	if body, _ := example.(map[string]interface{}); body["message"] != "hello" {
+		t.Errorf("Expected expanded example value, got %#v", example)
+	}
+
+	// Strict mode reports unset variables instead of expanding them to empty
+	os.Unsetenv("TEST_GREETING")
+	if _, err := NewWithOptions(specPath, Options{ExpandEnv: true, StrictEnv: true}); err == nil || !strings.Contains(err.Error(), "TEST_GREETING") {
+		t.Errorf("Expected an error naming TEST_GREETING, got %v", err)
+	}
+	if _, err := NewWithOptions(specPath, Options{ExpandEnv: true}); err != nil {
+		t.Errorf("Expected unset variables to expand to empty, got %v", err)
+	}
+}
+
+func TestOrderProperties(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Order API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: Users
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+components:
+  schemas:
+    Address:
+      type: object
+      properties:
+        zip:
+          type: string
+        city:
+          type: string
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+        id:
+          type: integer
+        address:
+          $ref: '#/components/schemas/Address'
+        active:
+          type: boolean
+`
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	value := []interface{}{map[string]interface{}{
+		"active":  true,
+		"address": map[string]interface{}{"city": "Springfield", "zip": "12345"},
+		"id":      1,
+		"name":    "Ada",
+		"extra":   "undeclared",
+	}}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "declared order",
+			opts:     Options{PropertyOrder: true},
+			expected: `[{"name":"Ada","id":1,"address":{"zip":"12345","city":"Springfield"},"active":true,"extra":"undeclared"}]`,
+		},
+		{
+			name:     "alphabetical without recorded positions",
+			opts:     Options{},
+			expected: `[{"active":true,"address":{"city":"Springfield","zip":"12345"},"id":1,"name":"Ada","extra":"undeclared"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewWithOptions(specPath, tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to create parser: %v", err)
+			}
+			_, mediaType, err := ResponseContent(parser.GetRoutes()[0].Operation, "200")
+			if err != nil {
+				t.Fatalf("Failed to get response content: %v", err)
+			}
+
+			// Marshal repeatedly to check that the order is stable
+			for i := 0; i < 5; i++ {
+				body, err := json.Marshal(parser.OrderProperties(value, mediaType.Schema.Value))
+				if err != nil {
+					t.Fatalf("Failed to marshal: %v", err)
+				}
+				if string(body) != tt.expected {
+					t.Fatalf("Expected %s, got %s", tt.expected, body)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrentLoadsWithPropertyOrder(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Order API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: Users
+          content:
+            application/json:
+              example:
+                name: Ada
+                id: 1
+`
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(spec), 0o600); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(propertyOrder bool) {
+			defer wg.Done()
+			parser, err := NewWithOptions(specPath, Options{PropertyOrder: propertyOrder})
+			if err != nil {
+				errs <- err
+				return
+			}
+			example, err := parser.GetExampleResponse(parser.GetRoutes()[0].Operation, "200", "")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, ok := example.(map[string]interface{})["__origin__"]; ok {
+				errs <- fmt.Errorf("example has source positions: %v", example)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestOperationDelay(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{"300ms", 300 * time.Millisecond, false},
+		{"2s", 2 * time.Second, false},
+		{"250", 250 * time.Millisecond, false},
+		{float64(40), 40 * time.Millisecond, false},
+		{"10m", constants.MaxDelayDuration, false},
+		{"soon", 0, true},
+		{"-1s", 0, true},
+		{true, 0, true},
+	}
+
+	for _, tt := range tests {
+		operation := &openapi3.Operation{}
+		if tt.value != nil {
+			operation.Extensions = map[string]interface{}{constants.ExtensionMockDelay: tt.value}
+		}
+		got, err := OperationDelay(operation)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("OperationDelay(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("OperationDelay(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNew_GzippedSpec(t *testing.T) {
+	data, err := os.ReadFile("../../examples/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read petstore spec: %v", err)
+	}
+	compress := func(path string) {
+		t.Helper()
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+		zw := gzip.NewWriter(f)
+		if _, err := zw.Write(data); err != nil {
+			t.Fatalf("failed to compress spec: %v", err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("failed to compress spec: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("failed to close %s: %v", path, err)
+		}
+	}
+
+	dir := t.TempDir()
+	gzipped := filepath.Join(dir, "petstore.yaml.gz")
+	compress(gzipped)
+	// Gzipped content is also detected without the extension
+	unmarked := filepath.Join(dir, "petstore.yaml")
+	compress(unmarked)
+
+	for _, path := range []string{gzipped, unmarked} {
+		p, err := New(path)
+		if err != nil {
+			t.Fatalf("New(%s) failed: %v", path, err)
+		}
+		if len(p.GetRoutes()) == 0 {
+			t.Errorf("expected routes from %s", path)
+		}
+		if string(p.RawSpec()) != string(data) {
+			t.Errorf("expected RawSpec to return the decompressed spec for %s", path)
+		}
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.yaml.gz")
+	if err := os.WriteFile(corrupt, data, 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	if _, err := New(corrupt); err == nil || !strings.Contains(err.Error(), "decompress") {
+		t.Errorf("expected a .gz file that is not gzipped to fail, got %v", err)
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Warmup API
+  version: "1.0"
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: Users
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                    format: uuid
+  /orders:
+    get:
+      responses:
+        "200":
+          description: Orders
+          content:
+            application/json:
+              example: {"kind": "default"}
+              examples:
+                empty:
+                  value: {"kind": "empty"}
+                full:
+                  value: {"kind": "full"}
+        "404":
+          description: Not found
+`
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	p, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if entries := p.Warmup(); entries != 4 {
+		t.Errorf("expected 4 warmed responses, got %d", entries)
+	}
+
+	users, _ := p.FindOperation("GET /users")
+	orders, _ := p.FindOperation("GET /orders")
+	first, err := p.GetExampleResponse(users.Operation, "200", "")
+	if err != nil {
+		t.Fatalf("GetExampleResponse failed: %v", err)
+	}
+	again, _ := p.GetExampleResponse(users.Operation, "200", "")
+	if first.(map[string]interface{})["id"] != again.(map[string]interface{})["id"] {
+		t.Error("expected the warmed response to be served from the cache")
+	}
+
+	// Operations without an operationId are cached separately
+	order, _ := p.GetExampleResponse(orders.Operation, "200", "")
+	if kind := order.(map[string]interface{})["kind"]; kind != "default" {
+		t.Errorf("expected the orders example, got %v", order)
+	}
+}
+
+func TestServerBasePaths(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Servers API
+  version: "1.0"
+servers:
+  - url: /api/v1/
+  - url: https://api.example.com/{version}
+    variables:
+      version:
+        default: v2
+  - url: https://staging.example.com/api/v1
+  - url: https://example.com
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: Pets
+`
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	p, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	want := []string{"/api/v1", "/v2", ""}
+	if got := p.ServerBasePaths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected base paths %v, got %v", want, got)
+	}
+}
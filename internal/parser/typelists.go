@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// normalizeNullTypes rewrites OpenAPI 3.1 type lists that include "null", such
+// as [integer, "null"], into the single type with nullable set. kin-openapi
+// only validates the 3.0 type names and rejects "null" otherwise.
+func normalizeNullTypes(doc *openapi3.T) {
+	n := &nullTypeNormalizer{seen: make(map[*openapi3.Schema]bool)}
+
+	if doc.Components != nil {
+		for _, schema := range doc.Components.Schemas {
+			n.schema(schema)
+		}
+		for _, parameter := range doc.Components.Parameters {
+			n.parameter(parameter)
+		}
+		for _, header := range doc.Components.Headers {
+			n.header(header)
+		}
+		for _, requestBody := range doc.Components.RequestBodies {
+			n.requestBody(requestBody)
+		}
+		for _, response := range doc.Components.Responses {
+			n.response(response)
+		}
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+	for _, pathItem := range doc.Paths.Map() {
+		for _, parameter := range pathItem.Parameters {
+			n.parameter(parameter)
+		}
+		for _, operation := range pathItem.Operations() {
+			for _, parameter := range operation.Parameters {
+				n.parameter(parameter)
+			}
+			n.requestBody(operation.RequestBody)
+			if operation.Responses != nil {
+				for _, response := range operation.Responses.Map() {
+					n.response(response)
+				}
+			}
+		}
+	}
+}
+
+// nullTypeNormalizer walks schemas once each, so recursive schemas terminate
+type nullTypeNormalizer struct {
+	seen map[*openapi3.Schema]bool
+}
+
+func (n *nullTypeNormalizer) schema(ref *openapi3.SchemaRef) {
+	if ref == nil || ref.Value == nil || n.seen[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	n.seen[schema] = true
+
+	if schema.Type != nil && len(*schema.Type) > 1 && schema.Type.Includes(openapi3.TypeNull) {
+		types := make(openapi3.Types, 0, len(*schema.Type)-1)
+		for _, typ := range *schema.Type {
+			if typ != openapi3.TypeNull {
+				types = append(types, typ)
+			}
+		}
+		schema.Type = &types
+		schema.Nullable = true
+	}
+
+	for _, property := range schema.Properties {
+		n.schema(property)
+	}
+	n.schema(schema.Items)
+	n.schema(schema.AdditionalProperties.Schema)
+	n.schema(schema.Not)
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, sub := range refs {
+			n.schema(sub)
+		}
+	}
+}
+
+func (n *nullTypeNormalizer) content(content openapi3.Content) {
+	for _, mediaType := range content {
+		if mediaType != nil {
+			n.schema(mediaType.Schema)
+		}
+	}
+}
+
+func (n *nullTypeNormalizer) parameter(ref *openapi3.ParameterRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	n.schema(ref.Value.Schema)
+	n.content(ref.Value.Content)
+}
+
+func (n *nullTypeNormalizer) header(ref *openapi3.HeaderRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	n.schema(ref.Value.Schema)
+	n.content(ref.Value.Content)
+}
+
+func (n *nullTypeNormalizer) requestBody(ref *openapi3.RequestBodyRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	n.content(ref.Value.Content)
+}
+
+func (n *nullTypeNormalizer) response(ref *openapi3.ResponseRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	for _, header := range ref.Value.Headers {
+		n.header(header)
+	}
+	n.content(ref.Value.Content)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"go.uber.org/zap"
+)
+
+// maintenanceStatus is the payload accepted and returned by the maintenance endpoint
+type maintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// cacheStats reports response cache effectiveness since the server started
+type cacheStats struct {
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Size       int    `json:"size"`
+	MaxEntries int    `json:"max_entries"`
+}
+
+// adminStats is the payload returned by the stats endpoint
+type adminStats struct {
+	Cache cacheStats `json:"cache"`
+}
+
+// reloadResult is the payload returned by the reload endpoint
+type reloadResult struct {
+	Routes int `json:"routes"`
+}
+
+// registerAdminRoutes registers the runtime admin endpoints
+func (s *Server) registerAdminRoutes(router *chi.Mux) {
+	router.Group(func(admin chi.Router) {
+		admin.Use(s.adminAuthMiddleware)
+		admin.Get(constants.PathAdminMaintenance, s.maintenanceStatusHandler)
+		admin.Post(constants.PathAdminMaintenance, s.maintenanceToggleHandler)
+		admin.Get(constants.PathAdminOutages, s.outagesStatusHandler)
+		admin.Post(constants.PathAdminOutages, s.outagesDisableHandler)
+		admin.Delete(constants.PathAdminOutages, s.outagesEnableHandler)
+		admin.Get(constants.PathAdminStats, s.statsHandler)
+		admin.Post(constants.PathAdminReload, s.reloadHandler)
+	})
+}
+
+// adminAuthMiddleware rejects admin requests without the configured bearer
+// token. Admin endpoints stay open when admin.token is empty.
+func (s *Server) adminAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := s.config.Admin.Token
+		if token != "" {
+			provided, ok := strings.CutPrefix(r.Header.Get(constants.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set(constants.HeaderWWWAuthenticate, "Bearer")
+				s.sendErrorResponse(w, r, http.StatusUnauthorized, "A valid admin token is required")
+				s.logger.Logger.Warn("Rejected unauthenticated admin request",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+				)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reloadHandler re-reads the spec on demand, for setups where file watching
+// is unreliable, and reports the number of routes now served. Swapping the
+// spec is refused unless admin.token is set, so it is never left open.
+func (s *Server) reloadHandler(w http.ResponseWriter, r *http.Request) {
+	if s.config.Admin.Token == "" {
+		s.sendErrorResponse(w, r, http.StatusForbidden, "Reloading requires admin.token to be configured")
+		s.logger.Logger.Warn("Refused admin reload without a configured admin token",
+			zap.String("remote_addr", r.RemoteAddr),
+		)
+		return
+	}
+	if err := s.Reload(r.Context()); err != nil {
+		s.sendErrorResponse(w, r, http.StatusInternalServerError, "Failed to reload spec: "+err.Error())
+		return
+	}
+
+	s.mu.RLock()
+	routes := len(s.routes)
+	s.mu.RUnlock()
+
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	_ = json.NewEncoder(w).Encode(reloadResult{Routes: routes})
+
+	s.logger.Logger.Info("Spec reloaded from admin endpoint",
+		zap.Int("routes", routes),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+}
+
+// statsHandler reports response cache hits, misses, and size
+func (s *Server) statsHandler(w http.ResponseWriter, r *http.Request) {
+	stats := adminStats{Cache: cacheStats{
+		Hits:       s.cacheHits.Load(),
+		Misses:     s.cacheMisses.Load(),
+		Size:       s.cache.len(),
+		MaxEntries: s.cache.maxEntries,
+	}}
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+// maintenanceStatusHandler reports whether maintenance mode is active
+func (s *Server) maintenanceStatusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	_ = json.NewEncoder(w).Encode(maintenanceStatus{Enabled: s.maintenance.Load()})
+}
+
+// maintenanceToggleHandler switches maintenance mode on or off at runtime
+func (s *Server) maintenanceToggleHandler(w http.ResponseWriter, r *http.Request) {
+	var status maintenanceStatus
+	if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+		s.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid maintenance request: "+err.Error())
+		return
+	}
+
+	s.maintenance.Store(status.Enabled)
+
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	_ = json.NewEncoder(w).Encode(status)
+
+	s.logger.Logger.Info("Maintenance mode updated",
+		zap.Bool("enabled", status.Enabled),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+}
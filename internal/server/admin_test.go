@@ -0,0 +1,336 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+)
+
+const adminTestSpec = `openapi: 3.0.0
+info:
+  title: Admin Test API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              example:
+                - name: Fido
+  /orders:
+    get:
+      operationId: listOrders
+      responses:
+        "200":
+          description: Orders
+          content:
+            application/json:
+              example:
+                - id: 1
+`
+
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminMaintenanceToggle(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+		cfg.Maintenance.RetryAfter = 90 * time.Second
+	})
+	handler := srv.buildHandler()
+
+	if rec := serve(handler, http.MethodGet, "/pets", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 before maintenance, got %d", rec.Code)
+	}
+
+	rec := serve(handler, http.MethodPost, "/admin/maintenance", `{"enabled": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 enabling maintenance, got %d", rec.Code)
+	}
+
+	rec = serve(handler, http.MethodGet, "/pets", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 during maintenance, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("expected Retry-After 90, got %q", got)
+	}
+
+	if rec := serve(handler, http.MethodGet, "/health", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected health to stay 200 during maintenance, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/ready", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected readiness to stay 200 during maintenance, got %d", rec.Code)
+	}
+
+	rec = serve(handler, http.MethodGet, "/admin/maintenance", "")
+	if !strings.Contains(rec.Body.String(), `"enabled":true`) {
+		t.Errorf("expected maintenance status to be enabled, got %s", rec.Body.String())
+	}
+
+	if rec := serve(handler, http.MethodPost, "/admin/maintenance", `{"enabled": false}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 disabling maintenance, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/pets", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after maintenance, got %d", rec.Code)
+	}
+}
+
+func TestAdminMaintenanceFromConfig(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Maintenance.Enabled = true
+	})
+	handler := srv.buildHandler()
+
+	if rec := serve(handler, http.MethodGet, "/orders", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when maintenance is enabled in config, got %d", rec.Code)
+	}
+
+	// Admin endpoints are not registered unless enabled
+	if rec := serve(handler, http.MethodPost, "/admin/maintenance", `{"enabled": false}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for disabled admin endpoint, got %d", rec.Code)
+	}
+}
+
+func TestAdminMaintenanceInvalidBody(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+	})
+
+	if rec := serve(srv.buildHandler(), http.MethodPost, "/admin/maintenance", `not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid body, got %d", rec.Code)
+	}
+}
+
+func TestAdminOutagesDisableOperation(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+	})
+	handler := srv.buildHandler()
+
+	if rec := serve(handler, http.MethodPost, "/admin/outages", `{"operation": "listPets"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 disabling operation, got %d", rec.Code)
+	}
+
+	if rec := serve(handler, http.MethodGet, "/pets", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for disabled operation, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/orders", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected sibling operation to keep working, got %d", rec.Code)
+	}
+
+	rec := serve(handler, http.MethodGet, "/admin/outages", "")
+	if !strings.Contains(rec.Body.String(), "listPets") {
+		t.Errorf("expected outage list to contain listPets, got %s", rec.Body.String())
+	}
+
+	if rec := serve(handler, http.MethodDelete, "/admin/outages", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 resetting outages, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/pets", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after reset, got %d", rec.Code)
+	}
+}
+
+func TestAdminOutagesFromConfig(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+		cfg.Outages.StatusCode = http.StatusBadGateway
+		cfg.Outages.Operations = []string{"get /orders"}
+	})
+	handler := srv.buildHandler()
+
+	if rec := serve(handler, http.MethodGet, "/orders", ""); rec.Code != http.StatusBadGateway {
+		t.Errorf("expected configured 502 for disabled operation, got %d", rec.Code)
+	}
+
+	// Re-enabling a single operation leaves the configured list otherwise intact
+	if rec := serve(handler, http.MethodDelete, "/admin/outages?operation=GET+/orders", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 enabling operation, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/orders", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after enabling operation, got %d", rec.Code)
+	}
+
+	// Resetting restores the configured outages
+	serve(handler, http.MethodDelete, "/admin/outages", "")
+	if rec := serve(handler, http.MethodGet, "/orders", ""); rec.Code != http.StatusBadGateway {
+		t.Errorf("expected configured outage after reset, got %d", rec.Code)
+	}
+}
+
+func TestAdminStatsCountsCacheHits(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+	})
+	handler := srv.buildHandler()
+
+	serve(handler, http.MethodGet, "/pets", "")
+	serve(handler, http.MethodGet, "/pets", "")
+	serve(handler, http.MethodGet, "/orders", "")
+	// Requests that bypass the cache are not lookups
+	serve(handler, http.MethodGet, "/orders?__noCache=true", "")
+
+	rec := serve(handler, http.MethodGet, "/admin/stats", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from stats, got %d", rec.Code)
+	}
+	var stats adminStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	expected := cacheStats{Hits: 1, Misses: 2, Size: 2, MaxEntries: config.DefaultCacheMaxEntries}
+	if stats.Cache != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats.Cache)
+	}
+}
+
+// postReload sends an authenticated reload request
+func postReload(handler http.Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/admin/reload", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminReloadRefreshesRoutes(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+		cfg.Admin.Token = "s3cret"
+	})
+	srv.dynamicHandler = NewDynamicHandler(srv.buildHandler())
+	handler := srv.dynamicHandler
+
+	if rec := serve(handler, http.MethodGet, "/users", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before the spec defines /users, got %d", rec.Code)
+	}
+
+	updated := adminTestSpec + `  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: Users
+          content:
+            application/json:
+              example:
+                - name: Ada
+`
+	if err := os.WriteFile(srv.config.SpecFile, []byte(updated), 0o644); err != nil {
+		t.Fatalf("failed to update spec: %v", err)
+	}
+
+	rec := postReload(handler, "s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from reload, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result reloadResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result.Routes != 3 {
+		t.Errorf("expected 3 routes after reload, got %d", result.Routes)
+	}
+	if rec := serve(handler, http.MethodGet, "/users", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected the reloaded route to be served, got %d", rec.Code)
+	}
+
+	// A broken spec keeps the last good one in service
+	if err := os.WriteFile(srv.config.SpecFile, []byte("openapi: [broken"), 0o644); err != nil {
+		t.Fatalf("failed to break spec: %v", err)
+	}
+	if rec := postReload(handler, "s3cret"); rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 for a spec that fails to parse, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/users", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected the last good spec to keep serving, got %d", rec.Code)
+	}
+}
+
+func TestAdminReloadRequiresToken(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+	})
+	srv.dynamicHandler = NewDynamicHandler(srv.buildHandler())
+
+	updated := adminTestSpec + `  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: Users
+`
+	if err := os.WriteFile(srv.config.SpecFile, []byte(updated), 0o644); err != nil {
+		t.Fatalf("failed to update spec: %v", err)
+	}
+
+	if rec := serve(srv.dynamicHandler, http.MethodPost, "/admin/reload", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 from reload without a configured admin token, got %d", rec.Code)
+	}
+	if rec := serve(srv.dynamicHandler, http.MethodGet, "/users", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected the spec not to be reloaded, got %d for /users", rec.Code)
+	}
+	// Other admin endpoints keep working without a token
+	if rec := serve(srv.dynamicHandler, http.MethodGet, "/admin/stats", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 from stats without a token, got %d", rec.Code)
+	}
+}
+
+func TestAdminToken(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Admin.Enabled = true
+		cfg.Admin.Token = "s3cret"
+	})
+	srv.dynamicHandler = NewDynamicHandler(srv.buildHandler())
+
+	tests := []struct {
+		authorization string
+		want          int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"s3cret", http.StatusUnauthorized},
+		{"Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		for _, target := range []string{"/admin/reload", "/admin/maintenance"} {
+			req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"enabled": false}`))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			srv.dynamicHandler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s with Authorization %q: expected %d, got %d", target, tt.authorization, tt.want, rec.Code)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("%s: expected a Bearer challenge on 401", target)
+			}
+		}
+	}
+
+	// Mock routes are unaffected by the admin token
+	if rec := serve(srv.dynamicHandler, http.MethodGet, "/pets", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected mock routes to stay open, got %d", rec.Code)
+	}
+}
@@ -110,9 +110,10 @@ paths:
 			t.Fatalf("Request failed: %v", err)
 		}
 		defer resp.Body.Close()
-		// The server currently returns 200 instead of 201, so we test for that.
-		if resp.StatusCode != http.StatusOK {
-			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		// POST /pets only declares a 201, which must not be confused with
+		// GET /pets even though neither operation has an operationId
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("Expected status %d, got %d", http.StatusCreated, resp.StatusCode)
 		}
 	})
 
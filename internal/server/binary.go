@@ -0,0 +1,15 @@
+package server
+
+import "encoding/base64"
+
+// encodeBinary returns the raw bytes of a binary example. Examples are
+// documented as base64 strings and decoded; strings that are not valid base64
+// are written as they are.
+func encodeBinary(example string) []byte {
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := encoding.DecodeString(example); err == nil {
+			return data
+		}
+	}
+	return []byte(example)
+}
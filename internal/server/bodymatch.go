@@ -0,0 +1,95 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+	"go.uber.org/zap"
+)
+
+// bodyMatchExample returns the named example of the first body_matching rule
+// for the route that the request's JSON body satisfies, or "" when none does.
+// The body is read and restored, so later handling still sees it.
+func (s *Server) bodyMatchExample(r *http.Request, route *parser.Route) string {
+	if s.config == nil || r.Body == nil {
+		return ""
+	}
+	var rules []config.BodyMatchRule
+	for _, rule := range s.config.BodyMatching.Rules {
+		if routeInOperations(route, map[string]struct{}{operationKey(rule.Operation): {}}) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		s.logger.Logger.Debug("Skipping body matching for a request without a JSON body",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Error(err),
+		)
+		return ""
+	}
+
+	for _, rule := range rules {
+		segments, err := config.ParseJSONPath(rule.Path)
+		if err != nil {
+			continue
+		}
+		if value, ok := lookupJSONPath(body, segments); ok && value == rule.Equals {
+			return rule.Example
+		}
+	}
+	return ""
+}
+
+// lookupJSONPath returns the scalar at the path segments in a decoded JSON
+// document, formatted as a string. Numbers use their shortest form, so 1.0
+// reads as "1"; null reads as "null".
+func lookupJSONPath(document interface{}, segments []string) (string, bool) {
+	current := document
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return "", false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", false
+			}
+			current = node[index]
+		default:
+			return "", false
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	case nil:
+		return "null", true
+	default:
+		return "", false
+	}
+}
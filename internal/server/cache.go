@@ -0,0 +1,78 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+)
+
+// responseCache is a least-recently-used cache of generated responses. Once it
+// holds maxEntries responses, storing another evicts the least recently used.
+type responseCache struct {
+	mu         sync.Mutex
+	maxEntries int // 0 means unbounded
+	entries    map[string]*list.Element
+	order      *list.List // most recently used at the front
+}
+
+// cacheEntry is the value of each element in responseCache.order
+type cacheEntry struct {
+	key      string
+	response cachedResponse
+}
+
+// newResponseCache creates a cache holding at most maxEntries responses
+func newResponseCache(maxEntries int) *responseCache {
+	return &responseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// get returns the response stored for key and marks it as recently used
+func (c *responseCache) get(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return cachedResponse{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).response, true
+}
+
+// put stores a response, evicting the least recently used entries beyond capacity
+func (c *responseCache) put(key string, response cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheEntry).response = response
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, response: response})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// clear removes every entry
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// len returns the number of cached responses
+func (c *responseCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResponseCache(2)
+	cache.put("a", cachedResponse{StatusCode: 200, Body: []byte("a")})
+	cache.put("b", cachedResponse{StatusCode: 200, Body: []byte("b")})
+
+	// Reading a makes b the least recently used entry
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	cache.put("c", cachedResponse{StatusCode: 200, Body: []byte("c")})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if response, ok := cache.get(key); !ok || string(response.Body) != key {
+			t.Errorf("Expected %s to stay cached", key)
+		}
+	}
+	if cache.len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.len())
+	}
+}
+
+func TestResponseCacheEvictsOldestBeyondCapacity(t *testing.T) {
+	cache := newResponseCache(3)
+	for i := 0; i < 5; i++ {
+		cache.put(fmt.Sprintf("key-%d", i), cachedResponse{StatusCode: 200})
+	}
+
+	if cache.len() != 3 {
+		t.Fatalf("Expected the cache to hold 3 entries, got %d", cache.len())
+	}
+	for i := 0; i < 5; i++ {
+		_, ok := cache.get(fmt.Sprintf("key-%d", i))
+		if want := i >= 2; ok != want {
+			t.Errorf("key-%d: expected cached=%v, got %v", i, want, ok)
+		}
+	}
+}
+
+func TestResponseCacheUnboundedAndClear(t *testing.T) {
+	cache := newResponseCache(0)
+	for i := 0; i < 100; i++ {
+		cache.put(fmt.Sprintf("key-%d", i), cachedResponse{StatusCode: 200})
+	}
+	if cache.len() != 100 {
+		t.Fatalf("Expected an unbounded cache to keep every entry, got %d", cache.len())
+	}
+
+	cache.clear()
+	if cache.len() != 0 {
+		t.Errorf("Expected an empty cache after clear, got %d", cache.len())
+	}
+	if _, ok := cache.get("key-0"); ok {
+		t.Error("Expected cleared entries to be gone")
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -177,3 +178,68 @@ func checkCORSHeaders(t *testing.T, resp *http.Response, testCase corsTestCase,
 		}
 	}
 }
+
+func TestCORSRouteOverrides(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: CORS Routes API
+  version: 1.0.0
+paths:
+  /public/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              example: {"public": true}
+  /private:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              example: {"public": false}
+`
+	srv := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Security.CORS.AllowedOrigins = []string{"https://app.example.com"}
+		cfg.Security.CORS.Routes = map[string]config.CORSRouteConfig{
+			"/public/{id}": {AllowedOrigins: []string{"*"}, MaxAge: 60},
+		}
+	})
+	handler := srv.buildHandler()
+
+	const origin = "https://other.example.com"
+	request := func(method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodOptions} {
+		public := request(method, "/public/42")
+		if got := public.Header().Get("Access-Control-Allow-Origin"); got != origin {
+			t.Errorf("%s /public/42: expected Access-Control-Allow-Origin %q, got %q", method, origin, got)
+		}
+		if got := public.Header().Get("Access-Control-Max-Age"); got != "60" {
+			t.Errorf("%s /public/42: expected route max age 60, got %q", method, got)
+		}
+
+		private := request(method, "/private")
+		if got := private.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("%s /private: expected no Access-Control-Allow-Origin, got %q", method, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// maxEchoBodySize bounds how much of a request body is reflected in an echo
+const maxEchoBodySize = 1 << 20
+
+// redactedValue replaces the values of redacted headers in echoes
+const redactedValue = "[REDACTED]"
+
+// echoResponse describes a received request
+type echoResponse struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   map[string][]string `json:"query"`
+	Headers map[string][]string `json:"headers"`
+	Body    string              `json:"body"`
+}
+
+// echoEnabled reports whether a request is reflected back instead of mocked. It
+// is on globally with echo.enabled or per request with __echo=true, and
+// __echo=false opts a request out.
+func (s *Server) echoEnabled(r *http.Request) bool {
+	if echo, err := strconv.ParseBool(r.URL.Query().Get(constants.QueryParamEcho)); err == nil {
+		return echo
+	}
+	return s.config != nil && s.config.Echo.Enabled
+}
+
+// sendEchoResponse returns a JSON description of the request, with the values
+// of the configured sensitive headers redacted
+func (s *Server) sendEchoResponse(w http.ResponseWriter, r *http.Request) {
+	redact := make(map[string]struct{})
+	if s.config != nil {
+		for _, name := range s.config.Echo.RedactHeaders {
+			redact[http.CanonicalHeaderKey(name)] = struct{}{}
+		}
+	}
+
+	headers := make(map[string][]string, len(r.Header))
+	for name, values := range r.Header {
+		if _, ok := redact[name]; ok {
+			values = []string{redactedValue}
+		}
+		headers[name] = values
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(io.LimitReader(r.Body, maxEchoBodySize)); err != nil {
+			s.sendErrorResponse(w, r, http.StatusBadRequest, "Failed to read request body")
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	query.Del(constants.QueryParamEcho)
+
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(echoResponse{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   query,
+		Headers: headers,
+		Body:    string(body),
+	})
+}
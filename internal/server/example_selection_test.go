@@ -0,0 +1,580 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+)
+
+func TestAcceptVersionSelectsExample(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Versioned API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      x-mock-versions:
+        "1": v1
+        "2": v2
+      responses:
+        "200":
+          description: Users
+          content:
+            application/json:
+              example:
+                name: default
+              examples:
+                v1:
+                  value:
+                    name: Ada Lovelace
+                v2:
+                  value:
+                    name:
+                      first: Ada
+                      last: Lovelace
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	get := func(version string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		if version != "" {
+			req.Header.Set("Accept-Version", version)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for version %q, got %d", version, rec.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		return body
+	}
+
+	if name, ok := get("1")["name"].(string); !ok || name != "Ada Lovelace" {
+		t.Errorf("expected v1 string name, got %v", name)
+	}
+
+	v2Name, ok := get("2")["name"].(map[string]interface{})
+	if !ok || v2Name["first"] != "Ada" {
+		t.Errorf("expected v2 structured name, got %v", v2Name)
+	}
+
+	if name := get("")["name"]; name != "default" {
+		t.Errorf("expected default example without Accept-Version, got %v", name)
+	}
+	if name := get("3")["name"]; name != "default" {
+		t.Errorf("expected default example for unmapped version, got %v", name)
+	}
+}
+
+func TestQueryParameterSelectsExample(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Search API
+  version: 1.0.0
+paths:
+  /search:
+    get:
+      operationId: search
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+      x-mock-query-examples:
+        status:
+          active: activeResults
+          archived: archivedResults
+        page:
+          "2": secondPage
+      responses:
+        "200":
+          description: Search results
+          content:
+            application/json:
+              example:
+                status: any
+              examples:
+                activeResults:
+                  value:
+                    status: active
+                archivedResults:
+                  value:
+                    status: archived
+                secondPage:
+                  value:
+                    status: second page
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	get := func(target string) interface{} {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d", target, rec.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		return body["status"]
+	}
+
+	// Alternate so the cache would expose a key that ignored the query
+	for i := 0; i < 2; i++ {
+		if status := get("/search?status=active"); status != "active" {
+			t.Errorf("expected the active example, got %v", status)
+		}
+		if status := get("/search?status=archived"); status != "archived" {
+			t.Errorf("expected the archived example, got %v", status)
+		}
+	}
+	if status := get("/search?status=deleted"); status != "any" {
+		t.Errorf("expected the default example for an unmapped value, got %v", status)
+	}
+	if status := get("/search?page=2"); status != "any" {
+		t.Errorf("expected parameters missing from the spec to be ignored, got %v", status)
+	}
+	if status := get("/search?status=active&__example=archivedResults"); status != "archived" {
+		t.Errorf("expected __example to take precedence, got %v", status)
+	}
+}
+
+func TestScenarioHeaderSelectsExample(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Scenario API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              example:
+                - name: Fido
+                - name: Rex
+              examples:
+                emptyList:
+                  value: []
+`
+	srv := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Scenario.Examples = map[string]string{"empty": "emptyList"}
+	})
+	handler := srv.buildHandler()
+
+	get := func(scenario string) []interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+		if scenario != "" {
+			req.Header.Set("X-Scenario", scenario)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for scenario %q, got %d", scenario, rec.Code)
+		}
+		var body []interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		return body
+	}
+
+	// Request the default first so a cached response could leak into the scenario
+	if pets := get(""); len(pets) != 2 {
+		t.Errorf("expected 2 pets by default, got %d", len(pets))
+	}
+	if pets := get("empty"); len(pets) != 0 {
+		t.Errorf("expected empty list for scenario, got %d pets", len(pets))
+	}
+	if pets := get("unknown"); len(pets) != 2 {
+		t.Errorf("expected default pets for unmapped scenario, got %d", len(pets))
+	}
+}
+
+func TestAcceptLanguageSelectsExample(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Greeting API
+  version: 1.0.0
+paths:
+  /greeting:
+    get:
+      operationId: getGreeting
+      responses:
+        "200":
+          description: Greeting
+          content:
+            application/json:
+              example:
+                message: Hi
+              examples:
+                english:
+                  value:
+                    message: Hello
+                french:
+                  value:
+                    message: Bonjour
+`
+	srv := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Language.Examples = map[string]string{"en": "english", "fr": "french"}
+	})
+	handler := srv.buildHandler()
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"fr", "Bonjour"},
+		{"en", "Hello"},
+		{"fr-CA, en;q=0.5", "Bonjour"},
+		{"de, en;q=0.8", "Hello"},
+		{"de", "Hi"},
+		{"", "Hi"},
+	}
+	// Requests for each language in turn, so a shared cache entry would surface
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		if tt.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %q, got %d", tt.acceptLanguage, rec.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if body["message"] != tt.want {
+			t.Errorf("Accept-Language %q: expected %q, got %v", tt.acceptLanguage, tt.want, body["message"])
+		}
+	}
+
+	keyFor := func(acceptLanguage string) string {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		return srv.generateCacheKey(http.MethodGet, "/greeting", req, "200", "")
+	}
+	if keyFor("fr") == keyFor("en") {
+		t.Error("expected the cache key to include the negotiated language")
+	}
+	if keyFor("fr") != keyFor("fr-FR") {
+		t.Error("expected requests negotiating the same language to share a cache key")
+	}
+}
+
+func TestBodyMatchingSelectsExample(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Body Matching API
+  version: 1.0.0
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+      responses:
+        "201":
+          description: Order
+          content:
+            application/json:
+              example:
+                tier: standard
+              examples:
+                premiumOrder:
+                  value:
+                    tier: premium
+                bulkOrder:
+                  value:
+                    tier: bulk
+`
+	srv := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.BodyMatching.Rules = []config.BodyMatchRule{
+			{Operation: "createOrder", Path: "$.customer.plan", Equals: "premium", Example: "premiumOrder"},
+			{Operation: "POST /orders", Path: "$.items[1].quantity", Equals: "100", Example: "bulkOrder"},
+		}
+	})
+	handler := srv.buildHandler()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "matched", body: `{"customer": {"plan": "premium"}}`, want: "premium"},
+		{name: "matched array index", body: `{"items": [{"quantity": 1}, {"quantity": 100.0}]}`, want: "bulk"},
+		{name: "unmatched", body: `{"customer": {"plan": "free"}}`, want: "standard"},
+		{name: "missing path", body: `{"items": []}`, want: "standard"},
+		{name: "malformed", body: `{"customer":`, want: "standard"},
+		{name: "empty", body: "", want: "standard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body["tier"] != tt.want {
+				t.Errorf("expected tier %q, got %v", tt.want, body["tier"])
+			}
+		})
+	}
+}
+
+func TestRoundRobinExampleRotation(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Rotating API
+  version: 1.0.0
+paths:
+  /status:
+    get:
+      operationId: getStatus
+      responses:
+        "200":
+          description: Status
+          content:
+            application/json:
+              examples:
+                healthy:
+                  value:
+                    state: healthy
+                degraded:
+                  value:
+                    state: degraded
+                down:
+                  value:
+                    state: down
+`
+	handler := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.ExampleRotation = config.ExampleRotationRoundRobin
+	}).buildHandler()
+
+	var states []string
+	for i := 0; i < 4; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		state, _ := body["state"].(string)
+		states = append(states, state)
+	}
+
+	want := []string{"degraded", "down", "healthy", "degraded"}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("expected rotation %v, got %v", want, states)
+		}
+	}
+}
+
+func TestOperationExampleRotation(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Rotating API
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      x-mock-rotation: roundrobin
+      responses:
+        "200":
+          description: Orders
+          content:
+            application/json:
+              examples:
+                a-empty:
+                  value:
+                    state: empty
+                b-single:
+                  value:
+                    state: single
+                c-many:
+                  value:
+                    state: many
+  /status:
+    get:
+      operationId: getStatus
+      responses:
+        "200":
+          description: Status
+          content:
+            application/json:
+              examples:
+                healthy:
+                  value:
+                    state: healthy
+                degraded:
+                  value:
+                    state: degraded
+`
+	// The global strategy stays "first" and the response cache stays on
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	get := func(path string) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d", path, rec.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		state, _ := body["state"].(string)
+		return state
+	}
+
+	var states []string
+	for i := 0; i < 5; i++ {
+		states = append(states, get("/orders"))
+	}
+	want := []string{"empty", "single", "many", "empty", "single"}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("expected rotation %v, got %v", want, states)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if state := get("/status"); state != "degraded" {
+			t.Fatalf("expected operations without x-mock-rotation to keep the first example, got %q", state)
+		}
+	}
+}
+
+func TestHALLinksFromOpenAPILinks(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Linked API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              example:
+                id: 42
+                name: Ada
+          links:
+            getUser:
+              operationId: getUser
+              parameters:
+                userId: $response.body#/id
+            listUsers:
+              operationId: listUsers
+              parameters:
+                name: $response.body#/name
+  /users/{userId}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: userId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: User
+  /users/search:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: Users
+`
+	newHandler := func(halLinks bool) http.Handler {
+		return newSpecTestServer(t, spec, func(cfg *config.Config) {
+			cfg.Server.HALLinks = halLinks
+		}).buildHandler()
+	}
+	post := func(handler http.Handler) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d", rec.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		return body
+	}
+
+	if _, ok := post(newHandler(false))["_links"]; ok {
+		t.Fatal("expected no _links unless server.hal_links is enabled")
+	}
+
+	handler := newHandler(true)
+	links, ok := post(handler)["_links"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected _links object in response")
+	}
+	want := map[string]string{
+		"self":      "/users",
+		"getUser":   "/users/42",
+		"listUsers": "/users/search?name=Ada",
+	}
+	for name, href := range want {
+		link, ok := links[name].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected link %q, got %v", name, links)
+		}
+		if link["href"] != href {
+			t.Errorf("expected %s href %q, got %v", name, href, link["href"])
+		}
+
+		// Every link must resolve to a mocked route
+		rec := httptest.NewRecorder()
+		method := http.MethodGet
+		if name == "self" {
+			method = http.MethodPost
+		}
+		handler.ServeHTTP(rec, httptest.NewRequest(method, href, nil))
+		if rec.Code >= http.StatusBadRequest {
+			t.Errorf("expected %s link %q to resolve, got status %d", name, href, rec.Code)
+		}
+	}
+}
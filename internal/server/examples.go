@@ -0,0 +1,86 @@
+package server
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+	"github.com/leslieo2/go-spec-mock/internal/server/middleware"
+)
+
+// selectExampleName picks the named example for a request, in order of precedence:
+// the __example query parameter, the scenario header, body_matching rules, query
+// examples, the Accept-Version header, the Accept-Language header, then the
+// configured example rotation
+func (s *Server) selectExampleName(r *http.Request, route *parser.Route, statusCode string) string {
+	if exampleName := middleware.GetExampleNameFromContext(r); exampleName != "" {
+		return exampleName
+	}
+
+	if header := s.scenarioHeader(); header != "" {
+		if exampleName := s.config.Scenario.ExampleFor(r.Header.Get(header)); exampleName != "" {
+			return exampleName
+		}
+	}
+
+	if exampleName := s.bodyMatchExample(r, route); exampleName != "" {
+		return exampleName
+	}
+
+	if exampleName := parser.ExampleNameForQuery(route.Operation, r.URL.Query()); exampleName != "" {
+		return exampleName
+	}
+
+	if exampleName := parser.ExampleNameForVersion(route.Operation, r.Header.Get(constants.HeaderAcceptVersion)); exampleName != "" {
+		return exampleName
+	}
+
+	if _, exampleName := s.languages.negotiate(r.Header.Get(constants.HeaderAcceptLanguage)); exampleName != "" {
+		return exampleName
+	}
+
+	return s.rotateExample(route, statusCode)
+}
+
+// scenarioHeader returns the configured scenario header name, if any
+func (s *Server) scenarioHeader() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.Scenario.Header
+}
+
+// rotateExample returns the next named example for a response according to
+// the operation's x-mock-rotation or server.example_rotation, or an empty
+// string to use the default example
+func (s *Server) rotateExample(route *parser.Route, statusCode string) string {
+	if s.config == nil {
+		return ""
+	}
+
+	strategy := s.config.Server.ExampleRotation
+	switch override := parser.OperationRotation(route.Operation); override {
+	case config.ExampleRotationFirst, config.ExampleRotationRoundRobin, config.ExampleRotationRandom:
+		strategy = override
+	}
+	if strategy != config.ExampleRotationRoundRobin && strategy != config.ExampleRotationRandom {
+		return ""
+	}
+
+	names := s.currentParser().ExampleNames(route.Operation, statusCode)
+	if len(names) < 2 {
+		return ""
+	}
+
+	if strategy == config.ExampleRotationRandom {
+		return names[rand.IntN(len(names))] // #nosec G404 - example selection does not need a secure source
+	}
+
+	key := route.Method + " " + route.Path + ":" + statusCode
+	counter, _ := s.exampleCounters.LoadOrStore(key, &atomic.Uint64{})
+	next := counter.(*atomic.Uint64).Add(1) - 1
+	return names[next%uint64(len(names))]
+}
@@ -1,32 +1,51 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	_ "embed"
 	"encoding/json"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/leslieo2/go-spec-mock/internal/constants"
 	"github.com/leslieo2/go-spec-mock/internal/observability"
+	"github.com/leslieo2/go-spec-mock/internal/version"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
 // HealthHandler handles health check requests
 func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 
 	uptime := time.Since(s.startTime)
+	specFileReadable := s.specFileReadable()
 	health := observability.HealthStatus{
 		Status:    "healthy",
 		Timestamp: time.Now(),
-		Version:   "1.0.0",
+		Version:   version.Version,
+		Commit:    version.Commit,
+		BuildDate: version.Date,
 		Uptime:    uptime.String(),
 		Checks: map[string]bool{
-			"parser": s.parser != nil,
-			"routes": len(s.routes) > 0,
+			"parser":    s.parser != nil,
+			"routes":    len(s.routes) > 0,
+			"spec_file": specFileReadable,
 		},
 	}
 
+	statusCode := constants.StatusOK
+	if !specFileReadable {
+		// The in-memory spec may be stale if the file vanished after startup
+		health.Status = "unhealthy"
+		statusCode = constants.StatusServiceUnavailable
+	}
+
 	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
-	w.WriteHeader(constants.StatusOK)
+	w.WriteHeader(statusCode)
 	_ = json.NewEncoder(w).Encode(health)
 
 	s.logger.Logger.Debug("Health check completed",
@@ -35,10 +54,63 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// specFileReadable reports whether the configured spec file can still be opened
+func (s *Server) specFileReadable() bool {
+	if s.config == nil || s.config.SpecFile == "" {
+		return false
+	}
+	if s.config.SpecFile == constants.SpecFileStdin {
+		// A spec from stdin is read once and kept in memory
+		return true
+	}
+	f, err := os.Open(s.config.SpecFile) // #nosec G304 - spec path comes from validated configuration
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+}
+
+// tooStale reports whether reloads have kept failing for longer than
+// hot_reload.max_stale, so the last good spec should no longer count as ready
+func (s *Server) tooStale() bool {
+	if s.config == nil || s.config.HotReload.MaxStale <= 0 {
+		return false
+	}
+	since := s.staleSince.Load()
+	return since != 0 && time.Since(time.Unix(0, since)) > s.config.HotReload.MaxStale
+}
+
+// proxyTargetReachable probes proxy.target with a HEAD request when
+// proxy.health_check is set. Any response below 500 counts as reachable.
+func (s *Server) proxyTargetReachable(ctx context.Context) bool {
+	if s.config == nil || !s.config.Proxy.ProbesTarget() {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, min(s.config.Proxy.Timeout, constants.ProxyHealthCheckTimeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.config.Proxy.Target, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		s.logger.Logger.Warn("Proxy target is unreachable",
+			zap.String("target", s.config.Proxy.Target),
+			zap.Error(err),
+		)
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
+}
+
 // ReadinessHandler handles readiness check requests
 func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
 
-	ready := len(s.routes) > 0 && s.parser != nil
+	ready := len(s.routes) > 0 && s.parser != nil && s.warmupRemaining() <= 0 && !s.tooStale() &&
+		s.proxyTargetReachable(r.Context())
 
 	if ready {
 		w.WriteHeader(constants.StatusOK)
@@ -54,8 +126,20 @@ func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
-// DocumentationHandler serves API documentation
+// swaggerUIPage is the interactive API explorer served to browsers at /docs.
+// It loads the spec from /openapi.json.
+//
+//go:embed swagger-ui.html
+var swaggerUIPage []byte
+
+// DocumentationHandler serves API documentation: Swagger UI for browsers, and
+// a JSON summary of the routes otherwise
 func (s *Server) serveDocumentation(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get(constants.HeaderAccept), "text/html") {
+		w.Header().Set(constants.HeaderContentType, constants.ContentTypeHTML)
+		_, _ = w.Write(swaggerUIPage)
+		return
+	}
 
 	type RouteInfo struct {
 		Method      string `json:"method"`
@@ -66,6 +150,8 @@ func (s *Server) serveDocumentation(w http.ResponseWriter, r *http.Request) {
 	doc := struct {
 		Message       string      `json:"message"`
 		Version       string      `json:"version"`
+		Commit        string      `json:"commit"`
+		BuildDate     string      `json:"build_date"`
 		Environment   string      `json:"environment"`
 		Endpoints     []RouteInfo `json:"endpoints"`
 		Observability struct {
@@ -74,7 +160,9 @@ func (s *Server) serveDocumentation(w http.ResponseWriter, r *http.Request) {
 		} `json:"observability"`
 	}{
 		Message:     "Go-Spec-Mock Enterprise API Server",
-		Version:     "1.0.0",
+		Version:     version.Version,
+		Commit:      version.Commit,
+		BuildDate:   version.Date,
 		Environment: "production",
 		Endpoints:   make([]RouteInfo, 0, len(s.routes)),
 	}
@@ -97,3 +185,48 @@ func (s *Server) serveDocumentation(w http.ResponseWriter, r *http.Request) {
 		zap.Int("routes", len(s.routes)),
 	)
 }
+
+// serveOpenAPIJSON serves the currently loaded spec as JSON, reflecting hot reloads
+func (s *Server) serveOpenAPIJSON(w http.ResponseWriter, r *http.Request) {
+	s.serveSpec(w, r, constants.ContentTypeJSON, json.Marshal)
+}
+
+// serveOpenAPIYAML serves the currently loaded spec as YAML, reflecting hot reloads
+func (s *Server) serveOpenAPIYAML(w http.ResponseWriter, r *http.Request) {
+	s.serveSpec(w, r, constants.ContentTypeYAML, yaml.Marshal)
+}
+
+// serveSpec serves the parsed spec encoded with marshal
+func (s *Server) serveSpec(w http.ResponseWriter, r *http.Request, contentType string, marshal func(any) ([]byte, error)) {
+	p := s.currentParser()
+	if p == nil {
+		s.sendErrorResponse(w, r, constants.StatusServiceUnavailable, "Specification not loaded")
+		return
+	}
+
+	body, err := marshal(p.Document())
+	if err != nil {
+		s.sendErrorResponse(w, r, http.StatusInternalServerError, "Failed to serialize specification: "+err.Error())
+		return
+	}
+	w.Header().Set(constants.HeaderContentType, contentType)
+	_, _ = w.Write(body)
+}
+
+// serveRawSpec serves the spec file exactly as the parser read it
+func (s *Server) serveRawSpec(w http.ResponseWriter, r *http.Request) {
+	p := s.currentParser()
+	if p == nil {
+		s.sendErrorResponse(w, r, constants.StatusServiceUnavailable, "Specification not loaded")
+		return
+	}
+
+	contentType := constants.ContentTypeYAML
+	specFile := strings.TrimSuffix(strings.ToLower(s.config.SpecFile), ".gz")
+	if filepath.Ext(specFile) == ".json" ||
+		(s.config.SpecFile == constants.SpecFileStdin && bytes.HasPrefix(bytes.TrimSpace(p.RawSpec()), []byte("{"))) {
+		contentType = constants.ContentTypeJSON
+	}
+	w.Header().Set(constants.HeaderContentType, contentType)
+	_, _ = w.Write(p.RawSpec())
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+)
+
+// headResponseWriter answers a HEAD request from a GET handler: the body is
+// counted for Content-Length but never sent
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+// WriteHeader records the status code until the body length is known
+func (hw *headResponseWriter) WriteHeader(code int) {
+	if hw.status == 0 {
+		hw.status = code
+	}
+}
+
+// Write discards b, counting its length
+func (hw *headResponseWriter) Write(b []byte) (int, error) {
+	hw.length += len(b)
+	return len(b), nil
+}
+
+// finish sends the recorded status with the Content-Length of the GET body
+func (hw *headResponseWriter) finish() {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	if hw.length > 0 {
+		hw.Header().Set("Content-Length", strconv.Itoa(hw.length))
+	}
+	hw.ResponseWriter.WriteHeader(hw.status)
+}
+
+// autoHead reports whether HEAD is served for GET-only paths
+func (s *Server) autoHead() bool {
+	return s.config != nil && s.config.Server.AutoHead
+}
+
+// headFallback returns the GET route used to answer HEAD on a path that
+// declares no HEAD operation, when server.auto_head is enabled
+func (s *Server) headFallback(routes map[string]*parser.Route) (*parser.Route, bool) {
+	if !s.autoHead() {
+		return nil, false
+	}
+	if _, ok := routes[http.MethodHead]; ok {
+		return nil, false
+	}
+	route, ok := routes[http.MethodGet]
+	return route, ok
+}
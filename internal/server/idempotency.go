@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// idempotencyStore remembers the first response sent for each idempotency key.
+// Keys expire after a TTL and the oldest are evicted once the store is full.
+type idempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxKeys int
+	entries map[string]idempotencyEntry
+	order   []string // keys in insertion order, oldest first
+}
+
+// idempotencyEntry is a stored response and when it stops being replayed
+type idempotencyEntry struct {
+	response cachedResponse
+	expires  time.Time
+}
+
+// newIdempotencyStore creates a store, or returns nil when idempotency is disabled
+func newIdempotencyStore(cfg config.IdempotencyConfig) *idempotencyStore {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &idempotencyStore{
+		ttl:     cfg.TTL,
+		maxKeys: cfg.MaxKeys,
+		entries: make(map[string]idempotencyEntry),
+	}
+}
+
+// get returns the response stored for a key, if it has not expired
+func (st *idempotencyStore) get(key string) (cachedResponse, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.prune(time.Now())
+	entry, ok := st.entries[key]
+	return entry.response, ok
+}
+
+// putIfAbsent stores a response for a key unless one is already stored, and
+// returns the response that concurrent requests with the key should all send
+func (st *idempotencyStore) putIfAbsent(key string, response cachedResponse) cachedResponse {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now()
+	st.prune(now)
+	if entry, ok := st.entries[key]; ok {
+		return entry.response
+	}
+
+	st.entries[key] = idempotencyEntry{response: response, expires: now.Add(st.ttl)}
+	st.order = append(st.order, key)
+	for len(st.entries) > st.maxKeys {
+		st.evictOldest()
+	}
+	return response
+}
+
+// prune drops expired entries. All entries share one TTL, so they expire in
+// insertion order.
+func (st *idempotencyStore) prune(now time.Time) {
+	for len(st.order) > 0 && !now.Before(st.entries[st.order[0]].expires) {
+		st.evictOldest()
+	}
+}
+
+func (st *idempotencyStore) evictOldest() {
+	delete(st.entries, st.order[0])
+	st.order = st.order[1:]
+}
+
+// idempotencyKey returns the store key for a request carrying an
+// Idempotency-Key header, or "" when the request is not subject to idempotency
+func (s *Server) idempotencyKey(r *http.Request) string {
+	if s.idempotency == nil || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+		return ""
+	}
+
+	key := r.Header.Get(constants.HeaderIdempotencyKey)
+	if key == "" {
+		return ""
+	}
+	// Keys are scoped to the endpoint so clients may reuse them across endpoints
+	return r.Method + " " + r.URL.Path + " " + key
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+)
+
+const idempotencyTestSpec = `openapi: 3.0.0
+info:
+  title: Payments API
+  version: 1.0.0
+paths:
+  /payments:
+    post:
+      operationId: createPayment
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                    format: uuid
+`
+
+func TestIdempotencyKeyReplaysFirstResponse(t *testing.T) {
+	handler := newSpecTestServer(t, idempotencyTestSpec, func(cfg *config.Config) {
+		cfg.Idempotency.Enabled = true
+	}).buildHandler()
+
+	post := func(key string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/payments?__statusCode=201", nil)
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", rec.Code)
+		}
+		return rec
+	}
+
+	first := post("key-a")
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("Expected the first response not to be marked as replayed")
+	}
+
+	repeat := post("key-a")
+	if repeat.Body.String() != first.Body.String() {
+		t.Errorf("Expected identical responses for the same key, got %s and %s", first.Body.String(), repeat.Body.String())
+	}
+	if repeat.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected the repeated response to be marked as replayed")
+	}
+
+	if other := post("key-b"); other.Body.String() == first.Body.String() {
+		t.Errorf("Expected a different response for a different key, got %s twice", first.Body.String())
+	}
+}
+
+func TestIdempotencyStoreEvictsAndExpires(t *testing.T) {
+	store := newIdempotencyStore(config.IdempotencyConfig{Enabled: true, TTL: time.Hour, MaxKeys: 2})
+	for _, key := range []string{"a", "b", "c"} {
+		store.putIfAbsent(key, cachedResponse{StatusCode: http.StatusCreated, Body: []byte(key)})
+	}
+	if _, ok := store.get("a"); ok {
+		t.Error("Expected the oldest key to be evicted once the store is full")
+	}
+	if stored := store.putIfAbsent("c", cachedResponse{Body: []byte("other")}); string(stored.Body) != "c" {
+		t.Errorf("Expected the first stored response to win, got %s", stored.Body)
+	}
+
+	short := newIdempotencyStore(config.IdempotencyConfig{Enabled: true, TTL: 10 * time.Millisecond, MaxKeys: 10})
+	short.putIfAbsent("a", cachedResponse{})
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := short.get("a"); ok {
+		t.Error("Expected the key to expire after the TTL")
+	}
+
+	if newIdempotencyStore(config.IdempotencyConfig{}) != nil {
+		t.Error("Expected no store when idempotency is disabled")
+	}
+}
@@ -106,6 +106,29 @@ func startTestServer(t *testing.T, cfg *config.Config) (*testServer, func()) {
 	return ts, cleanup
 }
 
+// newSpecTestServer writes spec to a temporary file and creates a server for it.
+// The optional configure function can adjust the default configuration first.
+func newSpecTestServer(t *testing.T, spec string, configure func(cfg *config.Config)) *Server {
+	t.Helper()
+
+	specFile := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specFile, []byte(spec), 0o644); err != nil {
+		t.Fatalf("Failed to write spec file: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.SpecFile = specFile
+	if configure != nil {
+		configure(cfg)
+	}
+
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return srv
+}
+
 func waitForServerReady(t *testing.T, baseURL string, tlsEnabled bool) {
 	t.Helper()
 	deadline := time.Now().Add(10 * time.Second)
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"golang.org/x/text/language"
+)
+
+// languageMatcher negotiates the Accept-Language header against the tags in
+// language.examples
+type languageMatcher struct {
+	matcher  language.Matcher
+	tags     []string // configured tags, in matcher order
+	examples map[string]string
+}
+
+// newLanguageMatcher returns nil when no languages are configured
+func newLanguageMatcher(cfg config.LanguageConfig) *languageMatcher {
+	tags := cfg.Tags()
+	if len(tags) == 0 {
+		return nil
+	}
+	supported := make([]language.Tag, 0, len(tags))
+	for _, tag := range tags {
+		supported = append(supported, language.Make(tag))
+	}
+	return &languageMatcher{
+		matcher:  language.NewMatcher(supported),
+		tags:     tags,
+		examples: cfg.Examples,
+	}
+}
+
+// negotiate returns the configured tag that best matches an Accept-Language
+// header, such as "fr" for "fr-CA, en;q=0.5", and its named example. Both are
+// empty when no configured language is an acceptable match.
+func (m *languageMatcher) negotiate(acceptLanguage string) (tag, exampleName string) {
+	if m == nil || acceptLanguage == "" {
+		return "", ""
+	}
+	requested, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(requested) == 0 {
+		return "", ""
+	}
+	_, index, confidence := m.matcher.Match(requested...)
+	if confidence == language.No {
+		return "", ""
+	}
+	tag = m.tags[index]
+	return tag, m.examples[tag]
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+)
+
+// addHALLinks returns a copy of an object example with a HAL-style _links
+// section built from the OpenAPI links declared on the response. Link
+// parameters are resolved against the request and the generated body.
+func (s *Server) addHALLinks(r *http.Request, route *parser.Route, statusCode string, example interface{}) interface{} {
+	if s.config == nil || !s.config.Server.HALLinks {
+		return example
+	}
+
+	body, ok := example.(map[string]interface{})
+	if !ok {
+		return example
+	}
+
+	response := route.Operation.Responses.Value(statusCode)
+	if response == nil || response.Value == nil || len(response.Value.Links) == 0 {
+		return example
+	}
+
+	names := make([]string, 0, len(response.Value.Links))
+	for name := range response.Value.Links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	links := map[string]interface{}{
+		"self": map[string]interface{}{"href": r.URL.RequestURI()},
+	}
+	// Links stay under the base path the request was served at
+	basePath := ""
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		basePath = strings.TrimSuffix(rctx.RoutePattern(), route.Path)
+	}
+	for _, name := range names {
+		link := response.Value.Links[name]
+		if link == nil || link.Value == nil {
+			continue
+		}
+		// Only operationId links can be resolved; operationRef is not supported
+		target, ok := s.findRouteByOperationID(link.Value.OperationID)
+		if !ok {
+			continue
+		}
+		links[name] = map[string]interface{}{"href": linkHref(basePath+target.Path, link.Value.Parameters, r, body)}
+	}
+
+	// Examples are shared by the parser cache, so never modify them in place
+	result := make(map[string]interface{}, len(body)+1)
+	for key, value := range body {
+		result[key] = value
+	}
+	result["_links"] = links
+	return result
+}
+
+// findRouteByOperationID returns the route with the given operationId
+func (s *Server) findRouteByOperationID(operationID string) (parser.Route, bool) {
+	if operationID == "" {
+		return parser.Route{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, route := range s.routes {
+		if route.Operation != nil && route.Operation.OperationID == operationID {
+			return route, true
+		}
+	}
+	return parser.Route{}, false
+}
+
+// linkHref substitutes link parameters into the target path template. Parameters
+// that are not path placeholders are appended to the query string.
+func linkHref(path string, parameters map[string]interface{}, r *http.Request, body map[string]interface{}) string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	query := url.Values{}
+	for _, name := range names {
+		value, ok := resolveLinkExpression(parameters[name], r, body)
+		if !ok {
+			continue
+		}
+
+		// Parameter names may be qualified with their location, e.g. path.id
+		location, param, qualified := strings.Cut(name, ".")
+		if !qualified {
+			location, param = "", name
+		}
+
+		placeholder := "{" + param + "}"
+		if (location == "" || location == "path") && strings.Contains(path, placeholder) {
+			path = strings.ReplaceAll(path, placeholder, url.PathEscape(value))
+		} else if location == "" || location == "query" {
+			query.Set(param, value)
+		}
+	}
+
+	if len(query) > 0 {
+		return path + "?" + query.Encode()
+	}
+	return path
+}
+
+// resolveLinkExpression evaluates an OpenAPI runtime expression. Values that
+// are not expressions are used as constants.
+func resolveLinkExpression(expression interface{}, r *http.Request, body map[string]interface{}) (string, bool) {
+	expr, ok := expression.(string)
+	if !ok {
+		return fmt.Sprint(expression), true
+	}
+	if !strings.HasPrefix(expr, "$") {
+		return expr, true
+	}
+
+	switch {
+	case expr == "$url":
+		return r.URL.RequestURI(), true
+	case expr == "$method":
+		return r.Method, true
+	case strings.HasPrefix(expr, "$request.path."):
+		value := chi.URLParam(r, strings.TrimPrefix(expr, "$request.path."))
+		return value, value != ""
+	case strings.HasPrefix(expr, "$request.query."):
+		value := r.URL.Query().Get(strings.TrimPrefix(expr, "$request.query."))
+		return value, value != ""
+	case strings.HasPrefix(expr, "$request.header."):
+		value := r.Header.Get(strings.TrimPrefix(expr, "$request.header."))
+		return value, value != ""
+	case strings.HasPrefix(expr, "$response.body#"):
+		value, ok := jsonPointer(body, strings.TrimPrefix(expr, "$response.body#"))
+		if !ok || value == nil {
+			return "", false
+		}
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+// jsonPointer resolves an RFC 6901 JSON pointer against a decoded JSON document
+func jsonPointer(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	current := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
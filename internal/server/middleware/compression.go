@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// CompressionMiddleware gzips responses for clients that accept it. level is a
+// compress/gzip level from 1 to 9; 0 selects gzip.DefaultCompression.
+func CompressionMiddleware(level int) func(http.Handler) http.Handler {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add(constants.HeaderVary, constants.HeaderAcceptEncoding)
+			if r.Method == http.MethodHead || !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, level: level}
+			defer gw.close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get(constants.HeaderAcceptEncoding), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter holds back the headers until the first body byte, so
+// bodiless responses and already-encoded proxy responses pass through untouched
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	level       int
+	gz          *gzip.Writer
+	status      int
+	started     bool
+	passthrough bool
+}
+
+// WriteHeader records the status code until the body starts
+func (gw *gzipResponseWriter) WriteHeader(code int) {
+	if gw.status == 0 {
+		gw.status = code
+	}
+}
+
+// Write compresses b unless the response is passed through
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !gw.started {
+		if len(b) == 0 {
+			return 0, nil
+		}
+		gw.start(true)
+	}
+	if gw.passthrough {
+		return gw.ResponseWriter.Write(b)
+	}
+	return gw.gz.Write(b)
+}
+
+// start sends the headers, compressing the body when it has one and is not
+// already encoded
+func (gw *gzipResponseWriter) start(hasBody bool) {
+	gw.started = true
+	if gw.status == 0 {
+		gw.status = http.StatusOK
+	}
+
+	header := gw.Header()
+	if !hasBody || header.Get(constants.HeaderContentEncoding) != "" ||
+		gw.status == http.StatusNoContent || gw.status == http.StatusNotModified {
+		gw.passthrough = true
+	} else {
+		header.Set(constants.HeaderContentEncoding, "gzip")
+		header.Del("Content-Length")
+		// The level is validated by the caller, so this cannot fail
+		gw.gz, _ = gzip.NewWriterLevel(gw.ResponseWriter, gw.level)
+	}
+	gw.ResponseWriter.WriteHeader(gw.status)
+}
+
+// Flush pushes buffered compressed data to the client
+func (gw *gzipResponseWriter) Flush() {
+	if !gw.started {
+		gw.start(true)
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Flush()
+	}
+	if flusher, ok := gw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// close sends the headers of bodiless responses and finishes the gzip stream
+func (gw *gzipResponseWriter) close() {
+	if !gw.started {
+		if gw.status == 0 {
+			return
+		}
+		gw.start(false)
+	}
+	if gw.gz != nil {
+		_ = gw.gz.Close()
+	}
+}
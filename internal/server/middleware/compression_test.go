@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// compressibleBody is large and repetitive enough for levels to differ in size
+var compressibleBody = func() string {
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&b, `{"id":%d,"name":"pet-%d","tags":["dog","cat"]},`, i, i%37)
+	}
+	return b.String()
+}()
+
+func serveCompressed(t *testing.T, level int, handler http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	CompressionMiddleware(level)(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	body := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, compressibleBody)
+	}
+
+	rec := serveCompressed(t, 0, body, "br, gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected gzip Content-Encoding, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", got)
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body: %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil || string(decoded) != compressibleBody {
+		t.Errorf("Expected the decompressed body to match, err=%v", err)
+	}
+
+	for _, acceptEncoding := range []string{"", "br", "gzip;q=0"} {
+		rec := serveCompressed(t, 0, body, acceptEncoding)
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != compressibleBody {
+			t.Errorf("Expected no compression for Accept-Encoding %q", acceptEncoding)
+		}
+	}
+}
+
+func TestCompressionMiddleware_Level(t *testing.T) {
+	body := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, compressibleBody)
+	}
+
+	fastest := serveCompressed(t, gzip.BestSpeed, body, "gzip").Body.Len()
+	smallest := serveCompressed(t, gzip.BestCompression, body, "gzip").Body.Len()
+	if smallest >= fastest {
+		t.Errorf("Expected level 9 (%d bytes) to be smaller than level 1 (%d bytes)", smallest, fastest)
+	}
+}
+
+func TestCompressionMiddleware_Passthrough(t *testing.T) {
+	noContent := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	rec := serveCompressed(t, 0, noContent, "gzip")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 0 {
+		t.Errorf("Expected an uncompressed 204, got %d %q", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+
+	emptyOK := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}
+	rec = serveCompressed(t, 0, emptyOK, "gzip")
+	if rec.Code != http.StatusCreated || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected an uncompressed bodiless 201, got %d %q", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+
+	encoded := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = io.WriteString(w, "already encoded")
+	}
+	rec = serveCompressed(t, 0, encoded, "gzip")
+	if rec.Header().Get("Content-Encoding") != "br" || rec.Body.String() != "already encoded" {
+		t.Errorf("Expected an encoded response to pass through, got %q", rec.Body.String())
+	}
+}
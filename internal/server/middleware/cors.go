@@ -17,29 +17,59 @@ type CORSMiddleware struct {
 	AllowedHeaders   []string
 	AllowCredentials bool
 	MaxAge           int
+	PreflightStatus  int
 	logger           *zap.Logger
+
+	routes       map[string]*CORSMiddleware
+	routePattern func(r *http.Request) string
 }
 
-// NewCORSMiddleware creates a new CORS middleware
-func NewCORSMiddleware(allowedOrigins, allowedMethods, allowedHeaders []string, allowCredentials bool, maxAge int, logger *zap.Logger) *CORSMiddleware {
+// NewCORSMiddleware creates a new CORS middleware. A preflightStatus of 0 answers
+// preflight requests with 204 No Content.
+func NewCORSMiddleware(allowedOrigins, allowedMethods, allowedHeaders []string, allowCredentials bool, maxAge, preflightStatus int, logger *zap.Logger) *CORSMiddleware {
+	if preflightStatus == 0 {
+		preflightStatus = http.StatusNoContent
+	}
 	return &CORSMiddleware{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   allowedMethods,
 		AllowedHeaders:   allowedHeaders,
 		AllowCredentials: allowCredentials,
 		MaxAge:           maxAge,
+		PreflightStatus:  preflightStatus,
 		logger:           logger,
 	}
 }
 
+// WithRoutes makes the middleware route-aware: requests whose route pattern,
+// as reported by routePattern, has an entry in routes are handled with that
+// entry's settings instead
+func (c *CORSMiddleware) WithRoutes(routes map[string]*CORSMiddleware, routePattern func(r *http.Request) string) *CORSMiddleware {
+	c.routes = routes
+	c.routePattern = routePattern
+	return c
+}
+
+// forRequest returns the middleware whose settings apply to the request
+func (c *CORSMiddleware) forRequest(r *http.Request) *CORSMiddleware {
+	if len(c.routes) == 0 || c.routePattern == nil {
+		return c
+	}
+	if route, ok := c.routes[c.routePattern(r)]; ok {
+		return route
+	}
+	return c
+}
+
 // Handler returns the CORS middleware handler
 func (c *CORSMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cors := c.forRequest(r)
 		origin := r.Header.Get(constants.HeaderOrigin)
 
 		// Check if origin is allowed
 		allowed := false
-		for _, allowedOrigin := range c.AllowedOrigins {
+		for _, allowedOrigin := range cors.AllowedOrigins {
 			if allowedOrigin == "*" || allowedOrigin == origin {
 				allowed = true
 				break
@@ -48,43 +78,43 @@ func (c *CORSMiddleware) Handler(next http.Handler) http.Handler {
 
 		if allowed {
 			w.Header().Set(constants.HeaderAccessControlAllowOrigin, origin)
-			if len(c.AllowedMethods) > 0 {
-				w.Header().Set(constants.HeaderAccessControlAllowMethods, strings.Join(c.AllowedMethods, ", "))
+			if len(cors.AllowedMethods) > 0 {
+				w.Header().Set(constants.HeaderAccessControlAllowMethods, strings.Join(cors.AllowedMethods, ", "))
 			}
-			if len(c.AllowedHeaders) > 0 {
-				w.Header().Set(constants.HeaderAccessControlAllowHeaders, strings.Join(c.AllowedHeaders, ", "))
+			if len(cors.AllowedHeaders) > 0 {
+				w.Header().Set(constants.HeaderAccessControlAllowHeaders, strings.Join(cors.AllowedHeaders, ", "))
 			}
-			if c.AllowCredentials {
+			if cors.AllowCredentials {
 				w.Header().Set(constants.HeaderAccessControlAllowCredentials, "true")
 			}
-			if c.MaxAge > 0 {
-				w.Header().Set(constants.HeaderAccessControlMaxAge, fmt.Sprintf("%d", c.MaxAge))
+			if cors.MaxAge > 0 {
+				w.Header().Set(constants.HeaderAccessControlMaxAge, fmt.Sprintf("%d", cors.MaxAge))
 			}
 
-			c.logger.Debug("CORS headers applied",
+			cors.logger.Debug("CORS headers applied",
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
 				zap.String("origin", origin),
 				zap.String("remote_addr", r.RemoteAddr),
 			)
 		} else if origin != "" {
-			c.logger.Warn("CORS origin not allowed",
+			cors.logger.Warn("CORS origin not allowed",
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
 				zap.String("origin", origin),
 				zap.String("remote_addr", r.RemoteAddr),
-				zap.Strings("allowed_origins", c.AllowedOrigins),
+				zap.Strings("allowed_origins", cors.AllowedOrigins),
 			)
 		}
 
 		// Handle preflight requests
 		if r.Method == constants.MethodOPTIONS {
-			c.logger.Debug("CORS preflight request handled",
+			cors.logger.Debug("CORS preflight request handled",
 				zap.String("path", r.URL.Path),
 				zap.String("origin", origin),
 				zap.String("remote_addr", r.RemoteAddr),
 			)
-			w.WriteHeader(http.StatusOK)
+			w.WriteHeader(cors.PreflightStatus)
 			return
 		}
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,46 +11,152 @@ import (
 	"go.uber.org/zap"
 )
 
-// DelayMiddleware creates a middleware that simulates network latency
-func DelayMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+// DelayMiddleware creates a middleware that simulates network latency. The base delay
+// is applied to every request except health and readiness checks, and any per-request
+// __delay is added on top of it. With __delayStatus, the per-request delay is only
+// applied once the response status is known, and only when it is one of the listed codes.
+func DelayMiddleware(baseDelay time.Duration, logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var delayDuration time.Duration
+			if r.URL.Path != constants.PathHealth && r.URL.Path != constants.PathReady {
+				delayDuration = baseDelay
+			}
+
 			// Check for delay parameter
+			var requestDelay time.Duration
 			if delayParam := r.URL.Query().Get(constants.QueryParamDelay); delayParam != "" {
 				// Parse delay duration
-				delayDuration, err := parseDelay(delayParam)
+				parsed, err := parseDelay(delayParam)
 				if err != nil {
 					logger.Warn("Invalid delay parameter",
 						zap.String("delay", delayParam),
 						zap.String("path", r.URL.Path),
 						zap.Error(err),
 					)
-				} else if delayDuration > 0 {
-					// Apply the delay
-					select {
-					case <-time.After(delayDuration):
-						// Delay completed, continue with request
-					case <-r.Context().Done():
-						// Request was cancelled during delay
-						logger.Debug("Request cancelled during delay",
-							zap.String("path", r.URL.Path),
-							zap.Duration("delay", delayDuration),
-						)
-						return
-					}
-
-					logger.Debug("Applied response delay",
+				} else {
+					requestDelay = parsed
+				}
+			}
+
+			// Check for the status codes the request delay is limited to
+			var statuses map[int]bool
+			if statusParam := r.URL.Query().Get(constants.QueryParamDelayStatus); statusParam != "" && requestDelay > 0 {
+				parsed, err := parseDelayStatuses(statusParam)
+				if err != nil {
+					logger.Warn("Invalid delay status parameter",
+						zap.String("delay_status", statusParam),
 						zap.String("path", r.URL.Path),
-						zap.Duration("delay", delayDuration),
+						zap.Error(err),
 					)
+				} else {
+					statuses = parsed
 				}
 			}
 
+			delayDuration, _ = validateDelay(delayDuration)
+			if statuses != nil {
+				// The total stays within the cap once the request delay is applied
+				total, _ := validateDelay(delayDuration + requestDelay)
+				w = &statusDelayWriter{
+					ResponseWriter: w,
+					request:        r,
+					statuses:       statuses,
+					delay:          total - delayDuration,
+					logger:         logger,
+				}
+			} else {
+				delayDuration, _ = validateDelay(delayDuration + requestDelay)
+			}
+
+			if !Sleep(r, delayDuration, logger) {
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// Sleep waits for delay, reporting false if the request was cancelled first
+func Sleep(r *http.Request, delay time.Duration, logger *zap.Logger) bool {
+	if delay <= 0 {
+		return true
+	}
+
+	select {
+	case <-time.After(delay):
+		// Delay completed, continue with request
+	case <-r.Context().Done():
+		// Request was cancelled during delay
+		logger.Debug("Request cancelled during delay",
+			zap.String("path", r.URL.Path),
+			zap.Duration("delay", delay),
+		)
+		return false
+	}
+
+	logger.Debug("Applied response delay",
+		zap.String("path", r.URL.Path),
+		zap.Duration("delay", delay),
+	)
+	return true
+}
+
+// statusDelayWriter delays the response headers when the status is one of statuses
+type statusDelayWriter struct {
+	http.ResponseWriter
+	request     *http.Request
+	statuses    map[int]bool
+	delay       time.Duration
+	logger      *zap.Logger
+	wroteHeader bool
+}
+
+func (w *statusDelayWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if w.statuses[code] {
+			Sleep(w.request, w.delay, w.logger)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusDelayWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer so streamed responses keep working
+func (w *statusDelayWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (w *statusDelayWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// parseDelayStatuses parses a comma-separated list of HTTP status codes
+func parseDelayStatuses(value string) (map[int]bool, error) {
+	statuses := make(map[int]bool)
+	for _, part := range strings.Split(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+		statuses[code] = true
+	}
+	return statuses, nil
+}
+
 // parseDelay parses a delay string into a time.Duration
 func parseDelay(delayStr string) (time.Duration, error) {
 	// Remove any whitespace
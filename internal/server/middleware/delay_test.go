@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -12,7 +13,7 @@ import (
 
 func TestDelayMiddleware(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	middleware := DelayMiddleware(logger)
+	middleware := DelayMiddleware(0, logger)
 
 	tests := []struct {
 		name           string
@@ -113,6 +114,84 @@ func TestDelayMiddleware(t *testing.T) {
 	}
 }
 
+func TestDelayMiddlewareBaseDelay(t *testing.T) {
+	middleware := DelayMiddleware(200*time.Millisecond, zap.NewNop())
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		target      string
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{name: "base delay only", target: "/pets", expectedMin: 190 * time.Millisecond, expectedMax: 300 * time.Millisecond},
+		{name: "base plus request delay", target: "/pets?__delay=200ms", expectedMin: 390 * time.Millisecond, expectedMax: 500 * time.Millisecond},
+		{name: "health exempt", target: constants.PathHealth, expectedMax: 100 * time.Millisecond},
+		{name: "readiness exempt", target: constants.PathReady, expectedMax: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.target, nil))
+			elapsed := time.Since(start)
+
+			if elapsed < tt.expectedMin || elapsed > tt.expectedMax {
+				t.Errorf("Expected delay between %v and %v, got %v", tt.expectedMin, tt.expectedMax, elapsed)
+			}
+		})
+	}
+}
+
+func TestDelayMiddlewareDelayStatus(t *testing.T) {
+	handler := DelayMiddleware(0, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+	}))
+
+	tests := []struct {
+		name        string
+		target      string
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{name: "matched status", target: "/pets?status=500&__delayStatus=500&__delay=200ms", expectedMin: 190 * time.Millisecond, expectedMax: 300 * time.Millisecond},
+		{name: "matched one of several", target: "/pets?status=503&__delayStatus=500,503&__delay=200ms", expectedMin: 190 * time.Millisecond, expectedMax: 300 * time.Millisecond},
+		{name: "unmatched status", target: "/pets?status=200&__delayStatus=500&__delay=200ms", expectedMax: 100 * time.Millisecond},
+		{name: "invalid status list delays every response", target: "/pets?status=200&__delayStatus=oops&__delay=200ms", expectedMin: 190 * time.Millisecond, expectedMax: 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", tt.target, nil))
+			elapsed := time.Since(start)
+
+			if elapsed < tt.expectedMin || elapsed > tt.expectedMax {
+				t.Errorf("Expected delay between %v and %v, got %v", tt.expectedMin, tt.expectedMax, elapsed)
+			}
+		})
+	}
+
+	t.Run("implicit 200 from Write", func(t *testing.T) {
+		handler := DelayMiddleware(0, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/pets?__delayStatus=200&__delay=200ms", nil))
+		if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+			t.Errorf("Expected the 200 response to be delayed, got %v", elapsed)
+		}
+		if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+			t.Errorf("Expected 200 ok, got %d %q", rec.Code, rec.Body.String())
+		}
+	})
+}
+
 func TestParseDelay(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -15,12 +15,17 @@ const (
 	ContextKeyExampleName = contextKey("exampleName")
 )
 
-// ExampleMiddleware creates a middleware that extracts example name from query parameters
+// ExampleMiddleware creates a middleware that extracts example name from the __example
+// query parameter or, failing that, a "Prefer: example=" directive
 func ExampleMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Check for example parameter
-			if exampleName := r.URL.Query().Get(constants.QueryParamExample); exampleName != "" {
+			// Check for example parameter, then the Prefer header
+			exampleName := r.URL.Query().Get(constants.QueryParamExample)
+			if exampleName == "" {
+				exampleName = preferDirective(r, preferExample)
+			}
+			if exampleName != "" {
 				// Store example name in request context for downstream handlers
 				ctx := context.WithValue(r.Context(), ContextKeyExampleName, exampleName)
 				r = r.WithContext(ctx)
@@ -38,6 +38,7 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Int("status_code", wrapped.statusCode),
 				zap.Duration("duration", duration),
 				zap.String("user_agent", r.UserAgent()),
+				zap.String("request_id", GetRequestIDFromContext(r)),
 			)
 		})
 	}
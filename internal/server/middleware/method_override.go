@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"go.uber.org/zap"
+)
+
+// overridableMethods are the methods a POST may be rewritten to
+var overridableMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodPut:    {},
+	http.MethodPatch:  {},
+	http.MethodDelete: {},
+	http.MethodHead:   {},
+}
+
+// MethodOverrideMiddleware creates a middleware that routes POST requests as the
+// method named in X-HTTP-Method-Override, for clients that can only send POST
+func MethodOverrideMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			override := strings.ToUpper(strings.TrimSpace(r.Header.Get(constants.HeaderMethodOverride)))
+			if r.Method == http.MethodPost && override != "" {
+				if _, ok := overridableMethods[override]; ok {
+					logger.Debug("Method override applied",
+						zap.String("path", r.URL.Path),
+						zap.String("method", override),
+					)
+					r.Method = override
+				} else {
+					logger.Warn("Ignoring unsupported method override",
+						zap.String("path", r.URL.Path),
+						zap.String("method", override),
+					)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
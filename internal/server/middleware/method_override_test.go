@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMethodOverrideMiddleware(t *testing.T) {
+	var served string
+	handler := MethodOverrideMiddleware(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = r.Method
+	}))
+
+	tests := []struct {
+		name     string
+		method   string
+		override string
+		want     string
+	}{
+		{name: "POST with DELETE override", method: http.MethodPost, override: "DELETE", want: http.MethodDelete},
+		{name: "override is case-insensitive", method: http.MethodPost, override: "patch", want: http.MethodPatch},
+		{name: "POST without override", method: http.MethodPost, want: http.MethodPost},
+		{name: "only POST is overridden", method: http.MethodGet, override: "DELETE", want: http.MethodGet},
+		{name: "unsupported override is ignored", method: http.MethodPost, override: "CONNECT", want: http.MethodPost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/pets/1", nil)
+			if tt.override != "" {
+				req.Header.Set("X-HTTP-Method-Override", tt.override)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if served != tt.want {
+				t.Errorf("Expected method %s, got %s", tt.want, served)
+			}
+		})
+	}
+}
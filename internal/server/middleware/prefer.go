@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// Prefer header directives understood by the mock, as used by tools like Prism
+const (
+	preferCode    = "code"
+	preferExample = "example"
+)
+
+// preferDirective returns the value of a preference in the request's Prefer
+// headers (RFC 7240), such as "404" for "Prefer: code=404". Preference names
+// are case-insensitive, values may be quoted, and parameters after ";" are
+// ignored. The first occurrence wins.
+func preferDirective(r *http.Request, name string) string {
+	for _, header := range r.Header.Values(constants.HeaderPrefer) {
+		for _, preference := range strings.Split(header, ",") {
+			preference, _, _ = strings.Cut(preference, ";")
+			token, value, _ := strings.Cut(preference, "=")
+			if !strings.EqualFold(strings.TrimSpace(token), name) {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+				value = value[1 : len(value)-1]
+			}
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"go.uber.org/zap"
+)
+
+func TestPreferDirective(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		code    string
+		example string
+	}{
+		{name: "no header"},
+		{name: "code only", headers: []string{"code=404"}, code: "404"},
+		{name: "code and example", headers: []string{"code=200, example=premium"}, code: "200", example: "premium"},
+		{name: "quoted value", headers: []string{`example="empty list"`}, example: "empty list"},
+		{name: "case-insensitive name and spaces", headers: []string{"respond-async, Code = 503"}, code: "503"},
+		{name: "parameters ignored", headers: []string{"code=201; foo=bar"}, code: "201"},
+		{name: "separate headers", headers: []string{"return=minimal", "example=emptyList"}, example: "emptyList"},
+		{name: "first occurrence wins", headers: []string{"code=404", "code=500"}, code: "404"},
+		{name: "other preferences only", headers: []string{"return=representation, wait=10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			for _, header := range tt.headers {
+				req.Header.Add(constants.HeaderPrefer, header)
+			}
+			if got := preferDirective(req, preferCode); got != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, got)
+			}
+			if got := preferDirective(req, preferExample); got != tt.example {
+				t.Errorf("Expected example %q, got %q", tt.example, got)
+			}
+		})
+	}
+}
+
+func TestPreferHeaderOverrides(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		prefer          string
+		expectedStatus  int
+		expectedExample string
+	}{
+		{name: "prefer code", prefer: "code=404", expectedStatus: 404},
+		{name: "prefer example", prefer: "example=emptyList", expectedStatus: constants.StatusOK, expectedExample: "emptyList"},
+		{name: "query takes precedence", query: "__statusCode=500&__example=premium", prefer: "code=404, example=emptyList", expectedStatus: 500, expectedExample: "premium"},
+		{name: "invalid prefer code", prefer: "code=abc", expectedStatus: constants.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStatus int
+			var gotExample string
+			handler := StatusCodeMiddleware(zap.NewNop())(ExampleMiddleware(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotStatus = constants.StatusOK
+				if statusCode, ok := r.Context().Value(constants.ContextKeyStatusCode).(int); ok {
+					gotStatus = statusCode
+				}
+				gotExample = GetExampleNameFromContext(r)
+			})))
+
+			req := httptest.NewRequest("GET", "/test?"+tt.query, nil)
+			req.Header.Set(constants.HeaderPrefer, tt.prefer)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotStatus != tt.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatus, gotStatus)
+			}
+			if gotExample != tt.expectedExample {
+				t.Errorf("Expected example %q, got %q", tt.expectedExample, gotExample)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// RecordingsFile is the JSON lines file proxied traffic is appended to
+const RecordingsFile = "recordings.jsonl"
+
+// Recording is a single proxied request and the response the backend returned
+type Recording struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Query       string `json:"query,omitempty"`
+	BodyHash    string `json:"request_body_sha256,omitempty"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	// Headers are the response headers set by the backend, such as Content-Encoding
+	Headers http.Header `json:"headers,omitempty"`
+	// Body is the raw response body, base64-encoded in JSON so that binary and
+	// compressed bodies are kept byte for byte
+	Body       []byte    `json:"body"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Recorder appends proxied request/response pairs to a JSON lines file
+type Recorder struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewRecorder creates a recorder writing to RecordingsFile inside dir,
+// creating the directory if needed
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	return &Recorder{path: filepath.Join(dir, RecordingsFile)}, nil
+}
+
+// Record writes the request and the response captured by rw as one line.
+// bodyHash is the HashRequestBody result for the request.
+func (rec *Recorder) Record(r *http.Request, bodyHash string, rw *RecordingResponseWriter) error {
+	line, err := json.Marshal(Recording{
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Query:       r.URL.RawQuery,
+		BodyHash:    bodyHash,
+		Status:      rw.Status(),
+		ContentType: rw.Header().Get(constants.HeaderContentType),
+		Headers:     rw.ResponseHeaders(),
+		Body:        rw.body.Bytes(),
+		RecordedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	// #nosec G304 - the recordings path is built from the operator-configured record_dir
+	file, err := os.OpenFile(rec.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open recordings file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return file.Close()
+}
+
+// HashRequestBody returns the hex SHA-256 of the request body, or "" for an
+// empty body, and restores the body so it can still be forwarded
+func HashRequestBody(r *http.Request) (string, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 {
+		return "", nil
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RecordingResponseWriter passes a response through while keeping a copy of
+// its status code, headers, and body
+type RecordingResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	before  http.Header // Headers set by middleware before the response was forwarded
+	headers http.Header // Headers added or changed by the backend
+}
+
+// NewRecordingResponseWriter wraps w so the response can be recorded. Headers
+// already set on w belong to the mock server, not the backend, and are not
+// recorded unless the backend changes them.
+func NewRecordingResponseWriter(w http.ResponseWriter) *RecordingResponseWriter {
+	return &RecordingResponseWriter{ResponseWriter: w, before: w.Header().Clone()}
+}
+
+// WriteHeader captures the status code and headers
+func (rw *RecordingResponseWriter) WriteHeader(code int) {
+	if rw.status == 0 {
+		rw.status = code
+		rw.captureHeaders()
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write tees the body into the recording buffer
+func (rw *RecordingResponseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+		rw.captureHeaders()
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// captureHeaders keeps the headers the backend added or changed
+func (rw *RecordingResponseWriter) captureHeaders() {
+	rw.headers = make(http.Header)
+	for name, values := range rw.Header() {
+		if !slices.Equal(values, rw.before[name]) {
+			rw.headers[name] = slices.Clone(values)
+		}
+	}
+}
+
+// ResponseHeaders returns the headers the backend set on the response
+func (rw *RecordingResponseWriter) ResponseHeaders() http.Header {
+	if rw.headers == nil {
+		rw.captureHeaders()
+	}
+	return rw.headers
+}
+
+// Flush supports streaming responses from the reverse proxy
+func (rw *RecordingResponseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *RecordingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Status returns the response status code, 200 if none was written explicitly
+func (rw *RecordingResponseWriter) Status() int {
+	if rw.status == 0 {
+		return http.StatusOK
+	}
+	return rw.status
+}
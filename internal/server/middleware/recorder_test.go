@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorderAppendsJSONLines(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recordings")
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	for _, target := range []string{"/users?page=2", "/orders"} {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		rec := httptest.NewRecorder()
+		rw := NewRecordingResponseWriter(rec)
+		backend.ServeHTTP(rw, req)
+
+		if rec.Code != http.StatusCreated || rec.Body.String() != `{"id":1}` {
+			t.Fatalf("Expected the response to pass through, got %d %q", rec.Code, rec.Body.String())
+		}
+		if err := recorder.Record(req, "", rw); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	file, err := os.Open(filepath.Join(dir, RecordingsFile))
+	if err != nil {
+		t.Fatalf("Failed to open recordings: %v", err)
+	}
+	defer file.Close()
+
+	var recordings []Recording
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var recording Recording
+		if err := json.Unmarshal(scanner.Bytes(), &recording); err != nil {
+			t.Fatalf("Invalid recording line %q: %v", scanner.Text(), err)
+		}
+		recordings = append(recordings, recording)
+	}
+
+	if len(recordings) != 2 {
+		t.Fatalf("Expected 2 recordings, got %d", len(recordings))
+	}
+	first := recordings[0]
+	if first.Method != http.MethodPost || first.Path != "/users" || first.Query != "page=2" {
+		t.Errorf("Unexpected request fields: %+v", first)
+	}
+	if first.Status != http.StatusCreated || first.ContentType != "application/json" || string(first.Body) != `{"id":1}` {
+		t.Errorf("Unexpected response fields: %+v", first)
+	}
+	if recordings[1].Path != "/orders" {
+		t.Errorf("Expected second recording for /orders, got %s", recordings[1].Path)
+	}
+}
+
+func TestRecorderKeepsCompressedBodiesAndHeaders(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write([]byte{0x00, 0xff, 0xfe, 'p', 'n', 'g'})
+	_ = zw.Close()
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(compressed.Bytes())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/logo.png", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "abc")
+	rw := NewRecordingResponseWriter(rec)
+	backend.ServeHTTP(rw, req)
+	if err := recorder.Record(req, "", rw); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, RecordingsFile))
+	if err != nil {
+		t.Fatalf("Failed to read recordings: %v", err)
+	}
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		t.Fatalf("Invalid recording %q: %v", data, err)
+	}
+
+	if !bytes.Equal(recording.Body, compressed.Bytes()) {
+		t.Errorf("Expected the gzip body byte for byte, got %v", recording.Body)
+	}
+	if got := recording.Headers.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Expected Content-Encoding gzip to be recorded, got %q", got)
+	}
+	if got := recording.Headers.Get("ETag"); got != `"v1"` {
+		t.Errorf("Expected ETag to be recorded, got %q", got)
+	}
+	if got := recording.Headers.Get("X-Request-ID"); got != "" {
+		t.Errorf("Expected headers set before forwarding not to be recorded, got %q", got)
+	}
+}
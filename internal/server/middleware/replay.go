@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// Replayer serves responses previously captured by a Recorder
+type Replayer struct {
+	recordings map[string]Recording
+	matchBody  bool
+}
+
+// NewReplayer loads RecordingsFile from dir. When several recordings share a
+// key the most recent one wins. With matchBody, recordings are also keyed by
+// the request body hash.
+func NewReplayer(dir string, matchBody bool) (*Replayer, error) {
+	// #nosec G304 - the recordings path is built from the operator-configured record_dir
+	file, err := os.Open(filepath.Join(dir, RecordingsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recordings: %w", err)
+	}
+	defer file.Close()
+
+	replayer := &Replayer{recordings: make(map[string]Recording), matchBody: matchBody}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), constants.ServerMaxRequestSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var recording Recording
+		if err := json.Unmarshal(scanner.Bytes(), &recording); err != nil {
+			return nil, fmt.Errorf("invalid recording on line %d: %w", line, err)
+		}
+		replayer.recordings[replayer.key(recording.Method, recording.Path, recording.BodyHash)] = recording
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recordings: %w", err)
+	}
+
+	return replayer, nil
+}
+
+// MatchesBody reports whether lookups need the request body hash
+func (rp *Replayer) MatchesBody() bool {
+	return rp.matchBody
+}
+
+// Lookup finds the recording for a request by method and path, and by body
+// hash when body matching is enabled
+func (rp *Replayer) Lookup(r *http.Request, bodyHash string) (Recording, bool) {
+	recording, ok := rp.recordings[rp.key(r.Method, r.URL.Path, bodyHash)]
+	return recording, ok
+}
+
+func (rp *Replayer) key(method, path, bodyHash string) string {
+	key := method + " " + path
+	if rp.matchBody {
+		key += " " + bodyHash
+	}
+	return key
+}
+
+// Replay writes a recorded response with the headers the backend sent.
+// ContentType covers recordings written without headers.
+func (rec Recording) Replay(w http.ResponseWriter) {
+	if rec.ContentType != "" {
+		w.Header().Set(constants.HeaderContentType, rec.ContentType)
+	}
+	for name, values := range rec.Headers {
+		w.Header()[name] = slices.Clone(values)
+	}
+	w.WriteHeader(rec.Status)
+	_, _ = w.Write(rec.Body)
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRecordings(t *testing.T, lines ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	data := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, RecordingsFile), []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write recordings: %v", err)
+	}
+	return dir
+}
+
+func TestReplayerLookup(t *testing.T) {
+	dir := writeRecordings(t,
+		`{"method":"GET","path":"/users","status":200,"content_type":"application/json","body":"eyJ2IjoxfQ=="}`,
+		`{"method":"GET","path":"/users","status":200,"content_type":"application/json","body":"eyJ2IjoyfQ=="}`,
+		`{"method":"DELETE","path":"/users/1","status":204,"body":""}`,
+	)
+	replayer, err := NewReplayer(dir, false)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+
+	recording, ok := replayer.Lookup(httptest.NewRequest(http.MethodGet, "/users?page=3", nil), "")
+	if !ok {
+		t.Fatal("Expected a recording for GET /users")
+	}
+	rec := httptest.NewRecorder()
+	recording.Replay(rec)
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"v":2}` || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected the latest recording to be replayed, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if _, ok := replayer.Lookup(httptest.NewRequest(http.MethodPost, "/users", nil), ""); ok {
+		t.Error("Expected no recording for POST /users")
+	}
+}
+
+func TestReplayerMatchBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(`{"q":"cats"}`))
+	hash, err := HashRequestBody(req)
+	if err != nil || hash == "" {
+		t.Fatalf("HashRequestBody() = %q, %v", hash, err)
+	}
+
+	dir := writeRecordings(t,
+		`{"method":"POST","path":"/search","request_body_sha256":"`+hash+`","status":200,"body":"Y2F0cw=="}`,
+		`{"method":"POST","path":"/search","request_body_sha256":"other","status":200,"body":"ZG9ncw=="}`,
+	)
+	replayer, err := NewReplayer(dir, true)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+
+	recording, ok := replayer.Lookup(req, hash)
+	if !ok || string(recording.Body) != "cats" {
+		t.Errorf("Expected the recording matching the body hash, got %+v", recording)
+	}
+	if _, ok := replayer.Lookup(req, ""); ok {
+		t.Error("Expected no recording for an unrecorded body")
+	}
+}
+
+func TestReplayBinaryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	body := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe, 0x00, 0x80}
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write(body)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/blob", nil)
+	rw := NewRecordingResponseWriter(httptest.NewRecorder())
+	backend.ServeHTTP(rw, req)
+	if err := recorder.Record(req, "", rw); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	replayer, err := NewReplayer(dir, false)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	recording, ok := replayer.Lookup(req, "")
+	if !ok {
+		t.Fatal("Expected a recording for GET /blob")
+	}
+	rec := httptest.NewRecorder()
+	recording.Replay(rec)
+
+	if rec.Code != http.StatusAccepted || !bytes.Equal(rec.Body.Bytes(), body) {
+		t.Errorf("Expected the recorded bytes to be replayed, got %d %v", rec.Code, rec.Body.Bytes())
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Expected Content-Encoding gzip, got %q", got)
+	}
+	if got := rec.Header().Values("Set-Cookie"); len(got) != 2 {
+		t.Errorf("Expected both Set-Cookie headers, got %v", got)
+	}
+}
+
+func TestNewReplayerRejectsInvalidLines(t *testing.T) {
+	if _, err := NewReplayer(writeRecordings(t, `not json`), false); err == nil {
+		t.Fatal("Expected error for an invalid recording line")
+	}
+	if _, err := NewReplayer(t.TempDir(), false); err == nil {
+		t.Fatal("Expected error when the recordings file is missing")
+	}
+}
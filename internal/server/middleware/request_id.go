@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// ContextKeyRequestID is the context key for the request ID
+const ContextKeyRequestID = contextKey("requestID")
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware creates a middleware that assigns each request an ID, taken from
+// the X-Request-Id header when present or generated otherwise, stores it in the request
+// context, and echoes it in the response header
+func RequestIDMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(constants.HeaderRequestID)
+			if !validRequestID(requestID) {
+				requestID = newRequestID()
+			}
+
+			w.Header().Set(constants.HeaderRequestID, requestID)
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestID)))
+		})
+	}
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ContextKeyRequestID, requestID)
+}
+
+// GetRequestIDFromContext retrieves the request ID from the request context
+func GetRequestIDFromContext(r *http.Request) string {
+	if requestID, ok := r.Context().Value(ContextKeyRequestID).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to echo and log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' && c != '_' && c != '.' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random 128-bit request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
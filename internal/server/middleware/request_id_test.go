@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = GetRequestIDFromContext(r)
+	}))
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "propagates incoming ID", incoming: "abc-123", keep: true},
+		{name: "generates when missing", incoming: ""},
+		{name: "replaces unsafe ID", incoming: "bad id\r\ninjected"},
+		{name: "replaces over-long ID", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/pets", nil)
+			if tt.incoming != "" {
+				req.Header.Set(constants.HeaderRequestID, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			echoed := w.Header().Get(constants.HeaderRequestID)
+			if echoed == "" || echoed != seen {
+				t.Fatalf("Expected response header to echo context ID %q, got %q", seen, echoed)
+			}
+			if tt.keep && echoed != tt.incoming {
+				t.Errorf("Expected incoming ID %q to be kept, got %q", tt.incoming, echoed)
+			}
+			if !tt.keep && echoed == tt.incoming {
+				t.Errorf("Expected a generated ID, got the incoming %q", echoed)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddlewareIncludesRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := RequestIDMiddleware()(LoggingMiddleware(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	req := httptest.NewRequest("GET", "/pets", nil)
+	req.Header.Set(constants.HeaderRequestID, "trace-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.FilterField(zap.String("request_id", "trace-42")).All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one log line with request_id trace-42, got %d", len(entries))
+	}
+}
@@ -33,3 +33,29 @@ func RequestSizeLimitMiddleware(maxRequestSize int64, logger *zap.Logger) func(h
 		})
 	}
 }
+
+// QueryLengthLimitMiddleware creates a middleware that rejects requests whose raw query
+// string exceeds maxQueryLength bytes, before it reaches cache key computation
+func QueryLengthLimitMiddleware(maxQueryLength int, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxQueryLength > 0 && len(r.URL.RawQuery) > maxQueryLength {
+				logger.Warn("Query length limit exceeded",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+					zap.Int("query_length", len(r.URL.RawQuery)),
+					zap.Int("max_query_length", maxQueryLength),
+				)
+
+				w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+				w.WriteHeader(http.StatusRequestURITooLong)
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"error": fmt.Sprintf("Query string too long, max length: %d bytes", maxQueryLength),
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestQueryLengthLimitMiddleware(t *testing.T) {
+	handler := QueryLengthLimitMiddleware(32, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+	}{
+		{name: "no query", target: "/pets", expectedStatus: http.StatusOK},
+		{name: "query within limit", target: "/pets?limit=10&offset=20", expectedStatus: http.StatusOK},
+		{name: "query at limit", target: "/pets?q=" + strings.Repeat("a", 30), expectedStatus: http.StatusOK},
+		{name: "query over limit", target: "/pets?q=" + strings.Repeat("a", 31), expectedStatus: http.StatusRequestURITooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestQueryLengthLimitMiddleware_Disabled(t *testing.T) {
+	handler := QueryLengthLimitMiddleware(0, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pets?q="+strings.Repeat("a", 10000), nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with limit disabled, got %d", w.Code)
+	}
+}
@@ -9,12 +9,17 @@ import (
 	"go.uber.org/zap"
 )
 
-// StatusCodeMiddleware creates a middleware that extracts and validates status code from query parameters
+// StatusCodeMiddleware creates a middleware that extracts and validates status code from
+// the __statusCode query parameter or, failing that, a "Prefer: code=" directive
 func StatusCodeMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Check for status code parameter
-			if statusCodeParam := r.URL.Query().Get(constants.QueryParamStatusCode); statusCodeParam != "" {
+			// Check for status code parameter, then the Prefer header
+			statusCodeParam := r.URL.Query().Get(constants.QueryParamStatusCode)
+			if statusCodeParam == "" {
+				statusCodeParam = preferDirective(r, preferCode)
+			}
+			if statusCodeParam != "" {
 				// Parse and validate status code
 				statusCode, err := parseStatusCode(statusCodeParam)
 				if err != nil {
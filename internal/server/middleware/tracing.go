@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware creates a middleware that records a server span per request,
+// continuing any trace propagated in the incoming traceparent header
+func TracingMiddleware(tracer trace.Tracer) func(http.Handler) http.Handler {
+	propagator := propagation.TraceContext{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			wrapped := &ResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			// The route template is only known once chi has routed the request
+			if routeCtx := chi.RouteContext(r.Context()); routeCtx != nil {
+				if pattern := routeCtx.RoutePattern(); pattern != "" {
+					span.SetName(r.Method + " " + pattern)
+					span.SetAttributes(semconv.HTTPRoute(pattern))
+				}
+			}
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(wrapped.statusCode))
+			if wrapped.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", wrapped.statusCode))
+			}
+		})
+	}
+}
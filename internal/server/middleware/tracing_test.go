@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	router := chi.NewRouter()
+	router.Use(TracingMiddleware(provider.Tracer("test")))
+	router.Get("/pets/{petId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest("GET", "/pets/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "GET /pets/{petId}" {
+		t.Errorf("Expected span name %q, got %q", "GET /pets/{petId}", span.Name())
+	}
+	if got := span.Parent().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected span to continue the incoming trace, got trace ID %s", got)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected error status for a 500 response, got %v", span.Status().Code)
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["http.route"].AsString() != "/pets/{petId}" {
+		t.Errorf("Expected http.route attribute /pets/{petId}, got %q", attrs["http.route"].AsString())
+	}
+	if attrs["http.response.status_code"].AsInt64() != http.StatusInternalServerError {
+		t.Errorf("Expected status code attribute 500, got %d", attrs["http.response.status_code"].AsInt64())
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+const (
+	contentTypeTextPlain   = "text/plain"
+	contentTypeOctetStream = "application/octet-stream"
+)
+
+// encodeMultipart renders an object example as a multipart/form-data body with
+// one part per property. It returns the body and the Content-Type, including the
+// boundary.
+func encodeMultipart(example interface{}, mediaType *openapi3.MediaType) ([]byte, string, error) {
+	fields, ok := example.(map[string]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("multipart responses require an object schema")
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, name := range names {
+		binary := isBinaryProperty(mediaType, name)
+		contentType, data, err := partContent(fields[name], encodingContentType(mediaType, name), binary)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode part %s: %w", name, err)
+		}
+
+		params := map[string]string{"name": name}
+		if binary {
+			params["filename"] = name
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", params))
+		header.Set(constants.HeaderContentType, contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// partContent chooses the content type of a part and serializes its value.
+// Objects and arrays become JSON, binary properties octet-stream, and everything
+// else plain text, unless the media type's encoding declares a content type.
+func partContent(value interface{}, declared string, binary bool) (string, []byte, error) {
+	contentType := declared
+	if contentType == "" {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			contentType = constants.ContentTypeJSON
+		default:
+			if binary {
+				contentType = contentTypeOctetStream
+			} else {
+				contentType = contentTypeTextPlain
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return contentType, nil, nil
+	case string:
+		return contentType, []byte(v), nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		return contentType, data, err
+	default:
+		if strings.HasSuffix(contentType, "json") {
+			data, err := json.Marshal(v)
+			return contentType, data, err
+		}
+		return contentType, []byte(fmt.Sprint(v)), nil
+	}
+}
+
+// encodingContentType returns the content type declared for a property in the
+// media type's encoding object, if any
+func encodingContentType(mediaType *openapi3.MediaType, name string) string {
+	if mediaType == nil || mediaType.Encoding == nil {
+		return ""
+	}
+	if encoding := mediaType.Encoding[name]; encoding != nil {
+		// Several comma-separated types may be listed; the first is used
+		contentType, _, _ := strings.Cut(encoding.ContentType, ",")
+		return strings.TrimSpace(contentType)
+	}
+	return ""
+}
+
+// isBinaryProperty reports whether a property is declared with format binary
+func isBinaryProperty(mediaType *openapi3.MediaType, name string) bool {
+	if mediaType == nil || mediaType.Schema == nil || mediaType.Schema.Value == nil {
+		return false
+	}
+	property := mediaType.Schema.Value.Properties[name]
+	return property != nil && property.Value != nil && property.Value.Format == "binary"
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+	"go.uber.org/zap"
+)
+
+// outageSet tracks operations disabled to simulate partial outages.
+// Operations are keyed by operationId or by "METHOD /path".
+type outageSet struct {
+	mu         sync.RWMutex
+	operations map[string]struct{}
+}
+
+// outageRequest is the payload accepted by the outages endpoint
+type outageRequest struct {
+	Operation string `json:"operation"`
+}
+
+// outageStatus is the payload returned by the outages endpoint
+type outageStatus struct {
+	Operations []string `json:"operations"`
+}
+
+// newOutageSet creates an outage set with the given operations disabled
+func newOutageSet(operations []string) *outageSet {
+	o := &outageSet{}
+	o.reset(operations)
+	return o
+}
+
+// reset replaces the disabled operations with the given list
+func (o *outageSet) reset(operations []string) {
+	disabled := make(map[string]struct{}, len(operations))
+	for _, operation := range operations {
+		disabled[operationKey(operation)] = struct{}{}
+	}
+
+	o.mu.Lock()
+	o.operations = disabled
+	o.mu.Unlock()
+}
+
+// disable marks an operation as unavailable
+func (o *outageSet) disable(operation string) {
+	o.mu.Lock()
+	o.operations[operationKey(operation)] = struct{}{}
+	o.mu.Unlock()
+}
+
+// enable makes a previously disabled operation available again
+func (o *outageSet) enable(operation string) {
+	o.mu.Lock()
+	delete(o.operations, operationKey(operation))
+	o.mu.Unlock()
+}
+
+// list returns the disabled operations in sorted order
+func (o *outageSet) list() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	operations := make([]string, 0, len(o.operations))
+	for operation := range o.operations {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// matches reports whether the route is disabled by operationId or method and path
+func (o *outageSet) matches(route *parser.Route) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return routeInOperations(route, o.operations)
+}
+
+// routeInOperations reports whether a set built with operationKey contains the
+// route by operationId or by method and path
+func routeInOperations(route *parser.Route, operations map[string]struct{}) bool {
+	if len(operations) == 0 {
+		return false
+	}
+	if route.Operation != nil && route.Operation.OperationID != "" {
+		if _, ok := operations[route.Operation.OperationID]; ok {
+			return true
+		}
+	}
+	_, ok := operations[operationKey(route.Method+" "+route.Path)]
+	return ok
+}
+
+// operationKey normalizes "get /pets" to "GET /pets" and leaves operationIds untouched
+func operationKey(operation string) string {
+	operation = strings.TrimSpace(operation)
+	if method, path, ok := strings.Cut(operation, " "); ok {
+		return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+	}
+	return operation
+}
+
+// outageStatusCode returns the status served by disabled operations
+func (s *Server) outageStatusCode() int {
+	if s.config.Outages.StatusCode == 0 {
+		return constants.StatusServiceUnavailable
+	}
+	return s.config.Outages.StatusCode
+}
+
+// outagesStatusHandler lists the currently disabled operations
+func (s *Server) outagesStatusHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeOutageStatus(w)
+}
+
+// outagesDisableHandler disables a single operation at runtime
+func (s *Server) outagesDisableHandler(w http.ResponseWriter, r *http.Request) {
+	var req outageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Operation) == "" {
+		s.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid outage request: an operation is required")
+		return
+	}
+
+	s.outages.disable(req.Operation)
+	s.writeOutageStatus(w)
+
+	s.logger.Logger.Info("Operation disabled",
+		zap.String("operation", req.Operation),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+}
+
+// outagesEnableHandler re-enables one operation, or resets to the configured
+// outages when no operation query parameter is given
+func (s *Server) outagesEnableHandler(w http.ResponseWriter, r *http.Request) {
+	if operation := r.URL.Query().Get("operation"); operation != "" {
+		s.outages.enable(operation)
+		s.logger.Logger.Info("Operation enabled", zap.String("operation", operation))
+	} else {
+		s.outages.reset(s.config.Outages.Operations)
+		s.logger.Logger.Info("Outages reset to configuration")
+	}
+	s.writeOutageStatus(w)
+}
+
+// writeOutageStatus writes the disabled operations as JSON
+func (s *Server) writeOutageStatus(w http.ResponseWriter) {
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	_ = json.NewEncoder(w).Encode(outageStatus{Operations: s.outages.list()})
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/leslieo2/go-spec-mock/internal/constants"
+)
+
+// responseOverride returns the body forced by the X-Mock-Response header,
+// reporting false when the header is absent or server.allow_response_override
+// is off
+func (s *Server) responseOverride(r *http.Request) (string, bool) {
+	if s.config == nil || !s.config.Server.AllowResponseOverride {
+		return "", false
+	}
+	values, ok := r.Header[http.CanonicalHeaderKey(constants.HeaderMockResponse)]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// sendResponseOverride writes an overridden body with the requested status,
+// as JSON when it parses as JSON and as plain text otherwise
+func (s *Server) sendResponseOverride(w http.ResponseWriter, r *http.Request, body string) {
+	contentType := constants.ContentTypeJSON
+	if !json.Valid([]byte(body)) {
+		contentType = contentTypeTextPlain
+	}
+	s.sendJSONResponse(w, parseStatusCode(getStatusCodeFromContext(r)), contentType, []byte(body))
+}
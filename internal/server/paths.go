@@ -0,0 +1,128 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// pathNormalizationMiddleware rewrites request paths that match no route to
+// the route they match once a trailing slash is dropped (server.strict_slash
+// false) or letter case is ignored (server.case_insensitive_paths). Paths that
+// already match a route are left alone.
+func (s *Server) pathNormalizationMiddleware(router *chi.Mux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if normalized, ok := s.normalizePath(router, r.URL.Path); ok {
+				r.URL.Path = normalized
+				r.URL.RawPath = ""
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// normalizePath returns the routed path that path leniently matches, reporting
+// false when path is already routed or nothing matches
+func (s *Server) normalizePath(router *chi.Mux, path string) (string, bool) {
+	if routable(router, path) {
+		return "", false
+	}
+
+	candidate := path
+	if !s.config.Server.IsStrictSlash() && len(candidate) > 1 {
+		if trimmed := strings.TrimRight(candidate, "/"); trimmed != "" {
+			candidate = trimmed
+		}
+	}
+	if s.config.Server.CaseInsensitivePaths && !routable(router, candidate) {
+		if folded, ok := s.foldPath(candidate); ok {
+			candidate = folded
+		}
+	}
+
+	if candidate == path || !routable(router, candidate) {
+		return "", false
+	}
+	return candidate, true
+}
+
+// routable reports whether the router has a route for path with any method
+func routable(router *chi.Mux, path string) bool {
+	for _, method := range allowedMethodCandidates {
+		if router.Match(chi.NewRouteContext(), method, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// foldPath returns path with its static segments replaced by those of the
+// first mock route, in sorted order, that matches it ignoring case. Segments
+// matched by path parameters keep the request's case.
+func (s *Server) foldPath(path string) (string, bool) {
+	s.mu.RLock()
+	patterns := make([]string, 0, len(s.routeMap))
+	for pattern := range s.routeMap {
+		patterns = append(patterns, pattern)
+	}
+	s.mu.RUnlock()
+	sort.Strings(patterns)
+
+	segments := strings.Split(path, "/")
+	for _, pattern := range patterns {
+		patternSegments := strings.Split(pattern, "/")
+		if len(patternSegments) != len(segments) {
+			continue
+		}
+		folded := make([]string, len(segments))
+		matched := true
+		for i, segment := range patternSegments {
+			switch {
+			case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && segments[i] != "":
+				folded[i] = segments[i]
+			case strings.EqualFold(segment, segments[i]):
+				folded[i] = segment
+			default:
+				matched = false
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			return strings.Join(folded, "/"), true
+		}
+	}
+	return "", false
+}
+
+// pathPrefixMiddleware rewrites request paths for deployments behind a
+// gateway: server.strip_path_prefix is removed from paths under it, then
+// server.add_path_prefix is prepended when that yields a routed path, so
+// /health and /docs keep working unprefixed.
+func (s *Server) pathPrefixMiddleware(router *chi.Mux) func(http.Handler) http.Handler {
+	strip := strings.TrimRight(s.config.Server.StripPathPrefix, "/")
+	add := strings.TrimRight(s.config.Server.AddPathPrefix, "/")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			if strip != "" && (path == strip || strings.HasPrefix(path, strip+"/")) {
+				path = strings.TrimPrefix(path, strip)
+				if path == "" {
+					path = "/"
+				}
+			}
+			if add != "" && path != add && !strings.HasPrefix(path, add+"/") && routable(router, add+path) {
+				path = add + path
+			}
+			if path != r.URL.Path {
+				r.URL.Path = path
+				r.URL.RawPath = ""
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
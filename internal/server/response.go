@@ -3,23 +3,33 @@ package server
 import (
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/leslieo2/go-spec-mock/internal/config"
 	"github.com/leslieo2/go-spec-mock/internal/constants"
 	"github.com/leslieo2/go-spec-mock/internal/parser"
+	"github.com/leslieo2/go-spec-mock/internal/server/middleware"
+	"go.uber.org/zap"
 )
 
 // ResponseGenerator handles response generation and caching
 
 // cachedResponse represents a cached HTTP response
 type cachedResponse struct {
-	StatusCode int
-	Body       []byte
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	Trailers    map[string]string
 }
 
 // generateCacheKey creates a cache key from request parameters
@@ -31,7 +41,8 @@ func (s *Server) generateCacheKey(method, path string, r *http.Request, statusCo
 	var params []string
 	for key, values := range query {
 		// Skip internal parameters that don't affect response content
-		if key == constants.QueryParamStatusCode || key == constants.QueryParamDelay || key == constants.QueryParamExample || key == "_" {
+		if key == constants.QueryParamStatusCode || key == constants.QueryParamDelay || key == constants.QueryParamDelayStatus ||
+			key == constants.QueryParamExample || key == constants.QueryParamNoCache || key == constants.QueryParamEcho || key == "_" {
 			continue
 		}
 		for _, value := range values {
@@ -57,6 +68,9 @@ func (s *Server) generateCacheKey(method, path string, r *http.Request, statusCo
 	// - Authentication context (if available)
 	// - Accept header for content negotiation
 	// - Content-Type for request body format
+	// - Accept-Version for versioned examples
+	// - Scenario header for header-driven examples
+	// - Negotiated Accept-Language for language examples
 	var contextParts []string
 
 	// Add authentication context if available
@@ -76,6 +90,20 @@ func (s *Server) generateCacheKey(method, path string, r *http.Request, statusCo
 		contextParts = append(contextParts, "content-type:"+contentType)
 	}
 
+	if version := r.Header.Get(constants.HeaderAcceptVersion); version != "" {
+		contextParts = append(contextParts, "version:"+version)
+	}
+
+	if header := s.scenarioHeader(); header != "" {
+		if scenario := r.Header.Get(header); scenario != "" {
+			contextParts = append(contextParts, "scenario:"+scenario)
+		}
+	}
+
+	if tag, _ := s.languages.negotiate(r.Header.Get(constants.HeaderAcceptLanguage)); tag != "" {
+		contextParts = append(contextParts, "language:"+tag)
+	}
+
 	// Build cache key with all components
 	cacheKey := method + ":" + path + ":" + statusCode
 	if exampleName != "" {
@@ -95,55 +123,232 @@ func (s *Server) generateCacheKey(method, path string, r *http.Request, statusCo
 	return cacheKey
 }
 
-// getCachedResponse retrieves a cached response if available
+// getCachedResponse retrieves a cached response if available, counting the
+// lookup as a hit or a miss
 func (s *Server) getCachedResponse(cacheKey string) (*cachedResponse, bool) {
-	if cached, ok := s.cache.Load(cacheKey); ok {
-		if response, ok := cached.(cachedResponse); ok {
-			return &response, true
-		}
+	if response, ok := s.cache.get(cacheKey); ok {
+		s.cacheHits.Add(1)
+		return &response, true
 	}
+	s.cacheMisses.Add(1)
 	return nil, false
 }
 
+// cachingEnabled reports whether the response cache applies to a request. It
+// is off per request with __noCache=true, and otherwise follows the
+// operation's x-mock-cache extension or, without one, cache.enabled.
+func (s *Server) cachingEnabled(r *http.Request, route *parser.Route) bool {
+	if noCache, err := strconv.ParseBool(r.URL.Query().Get(constants.QueryParamNoCache)); err == nil && noCache {
+		return false
+	}
+	if enabled, ok := parser.OperationCaching(route.Operation); ok {
+		return enabled
+	}
+	return s.config == nil || s.config.Cache.IsEnabled()
+}
+
+// applyOperationDelay waits for the operation's x-mock-delay unless the request
+// sets its own __delay, reporting false if the request was cancelled meanwhile
+func (s *Server) applyOperationDelay(r *http.Request, route *parser.Route, logger *zap.Logger) bool {
+	if r.URL.Query().Get(constants.QueryParamDelay) != "" {
+		return true
+	}
+	delay, err := parser.OperationDelay(route.Operation)
+	if err != nil {
+		logger.Warn("Ignoring invalid operation delay",
+			zap.String("path", route.Path),
+			zap.Error(err),
+		)
+		return true
+	}
+	return middleware.Sleep(r, delay, logger)
+}
+
+// exceedsGenerationTimeout rejects a response whose generation took longer
+// than server.generation_timeout with 503, reporting true when it did
+func (s *Server) exceedsGenerationTimeout(w http.ResponseWriter, r *http.Request, elapsed time.Duration, logger *zap.Logger) bool {
+	if s.config == nil || s.config.Server.GenerationTimeout <= 0 || elapsed <= s.config.Server.GenerationTimeout {
+		return false
+	}
+	s.sendErrorResponse(w, r, http.StatusServiceUnavailable,
+		fmt.Sprintf("Response generation took %s, exceeding the %s budget", elapsed, s.config.Server.GenerationTimeout))
+	logger.Warn("Response generation exceeded timeout",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.Duration("duration", elapsed),
+		zap.Duration("generation_timeout", s.config.Server.GenerationTimeout),
+	)
+	return true
+}
+
 // cacheResponse stores a response in the cache
-func (s *Server) cacheResponse(cacheKey string, statusCode int, body []byte) {
-	s.cache.Store(cacheKey, cachedResponse{
-		StatusCode: statusCode,
-		Body:       body,
-	})
+func (s *Server) cacheResponse(cacheKey string, response cachedResponse) {
+	s.cache.put(cacheKey, response)
+}
+
+// setCacheDebugHeaders reports the hashed cache key and whether it was a hit,
+// when server.cache_debug is enabled
+func (s *Server) setCacheDebugHeaders(w http.ResponseWriter, cacheKey string, hit bool) {
+	if s.config == nil || !s.config.Server.CacheDebug {
+		return
+	}
+
+	// The raw key can contain query values, so only expose a hash of it
+	sum := sha256.Sum256([]byte(cacheKey))
+	w.Header().Set(constants.HeaderMockCacheKey, fmt.Sprintf("%x", sum)[:16])
+	if hit {
+		w.Header().Set(constants.HeaderMockCache, "HIT")
+	} else {
+		w.Header().Set(constants.HeaderMockCache, "MISS")
+	}
+}
+
+// currentParser returns the parser serving requests. Reload can swap it while
+// requests are in flight, so callers take one snapshot and use it throughout.
+func (s *Server) currentParser() *parser.Parser {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser
 }
 
 // clearCache clears all cached responses
 func (s *Server) clearCache() {
-	// Create a new empty cache map
-	s.cache = &sync.Map{}
+	s.cache.clear()
 }
 
-// generateResponse generates a response for the given route and status code
-func (s *Server) generateResponse(route *parser.Route, statusCode string, exampleName string) ([]byte, int, error) {
-	example, err := s.parser.GetExampleResponse(route.Operation, statusCode, exampleName)
-	if err == nil {
-		buf, err := json.Marshal(example)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to serialize response: %w", err)
+// generateResponse generates a response for the given route and status code.
+// With fresh set, schema-based data is regenerated instead of taken from the
+// parser's example cache.
+func (s *Server) generateResponse(r *http.Request, route *parser.Route, statusCode string, exampleName string, fresh bool) (cachedResponse, error) {
+	p := s.currentParser()
+	exampleResponse := p.GetExampleResponse
+	if fresh {
+		exampleResponse = p.GenerateExampleResponse
+	}
+	// Data generated from path parameters depends on their values, so it is
+	// never shared across paths; other responses keep using the example cache
+	if params := pathParamValues(r); len(params) > 0 && s.dependsOnPath(route, params) {
+		exampleResponse = func(operation *openapi3.Operation, code string, name string) (interface{}, error) {
+			return p.GenerateExampleResponseForPath(operation, code, name, params)
 		}
-		return buf, parseStatusCode(statusCode), nil
+	}
+
+	example, err := exampleResponse(route.Operation, statusCode, exampleName)
+	if err == nil || errors.Is(err, parser.ErrNoContent) {
+		return s.encodeExample(r, route, example, err, statusCode)
 	}
 
 	// Try to find any 2xx response if requested status not found
 	for code := range route.Operation.Responses.Map() {
 		if strings.HasPrefix(code, "2") {
-			if example, err = s.parser.GetExampleResponse(route.Operation, code, exampleName); err == nil {
-				buf, err := json.Marshal(example)
-				if err != nil {
-					return nil, 0, fmt.Errorf("failed to serialize response: %w", err)
-				}
-				return buf, parseStatusCode(code), nil
+			if example, err = exampleResponse(route.Operation, code, exampleName); err == nil || errors.Is(err, parser.ErrNoContent) {
+				return s.encodeExample(r, route, example, err, code)
 			}
 		}
 	}
 
-	return nil, 0, fmt.Errorf("no example found for status code %s", statusCode)
+	return cachedResponse{}, fmt.Errorf("no example found for status code %s", statusCode)
+}
+
+// dependsOnPath reports whether the response generated for route varies with
+// the request's path parameters: stable UUIDs are derived from all of them, and
+// properties named after one take its value
+func (s *Server) dependsOnPath(route *parser.Route, params map[string]string) bool {
+	if s.config != nil && s.config.Generator.StableUUIDs {
+		return true
+	}
+	return parser.FillsPathParams(route.Operation, params)
+}
+
+// pathParamValues returns the request's path parameters by name. The last
+// parameter also fills an id property when it is named like petId or pet_id.
+func pathParamValues(r *http.Request) map[string]string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil
+	}
+
+	params := make(map[string]string, len(rctx.URLParams.Keys)+1)
+	last := ""
+	for i, key := range rctx.URLParams.Keys {
+		if key == "*" || i >= len(rctx.URLParams.Values) {
+			continue
+		}
+		params[key] = rctx.URLParams.Values[i]
+		last = key
+	}
+	if _, ok := params["id"]; !ok && (strings.HasSuffix(last, "Id") || strings.HasSuffix(last, "_id")) {
+		params["id"] = params[last]
+	}
+	return params
+}
+
+// encodeExample serializes an example in the response's media type. Responses
+// without content, and 204 and 304 responses, have an empty body.
+func (s *Server) encodeExample(r *http.Request, route *parser.Route, example interface{}, exampleErr error, code string) (cachedResponse, error) {
+	status := parseStatusCode(code)
+	if errors.Is(exampleErr, parser.ErrNoContent) || status == http.StatusNoContent || status == http.StatusNotModified {
+		return cachedResponse{StatusCode: status}, nil
+	}
+
+	contentType, mediaType, err := parser.ResponseContent(route.Operation, code)
+	if err != nil {
+		return cachedResponse{}, fmt.Errorf("failed to resolve response content: %w", err)
+	}
+
+	if contentType == constants.ContentTypeMultipartFormData {
+		body, formContentType, err := encodeMultipart(example, mediaType)
+		if err != nil {
+			return cachedResponse{}, fmt.Errorf("failed to serialize response: %w", err)
+		}
+		return cachedResponse{StatusCode: status, ContentType: formContentType, Body: body}, nil
+	}
+	if text, ok := example.(string); ok && parser.IsBinaryMediaType(contentType, mediaType) {
+		body := encodeBinary(text)
+		return cachedResponse{
+			StatusCode:  status,
+			ContentType: contentType,
+			Body:        body,
+			Trailers:    resolveTrailers(parser.ResponseTrailers(route.Operation, code), body),
+		}, nil
+	}
+
+	buf, err := s.marshalBody(s.addHALLinks(r, route, code, example), mediaType)
+	if err != nil {
+		return cachedResponse{}, fmt.Errorf("failed to serialize response: %w", err)
+	}
+	return cachedResponse{
+		StatusCode:  status,
+		ContentType: contentType,
+		Body:        buf,
+		Trailers:    resolveTrailers(parser.ResponseTrailers(route.Operation, code), buf),
+	}, nil
+}
+
+// marshalBody encodes a JSON response body as configured under response,
+// following the media type's schema for property order
+func (s *Server) marshalBody(body interface{}, mediaType *openapi3.MediaType) ([]byte, error) {
+	if s.config == nil {
+		return json.Marshal(body)
+	}
+	if s.config.Response.PreserveOrder && mediaType != nil && mediaType.Schema != nil {
+		body = s.currentParser().OrderProperties(body, mediaType.Schema.Value)
+	}
+	if s.config.Response.Pretty {
+		return json.MarshalIndent(body, "", strings.Repeat(" ", s.config.Response.IndentWidth()))
+	}
+	return json.Marshal(body)
+}
+
+// resolveTrailers replaces the TrailerValueSHA256 placeholder with the hex
+// SHA-256 digest of the body
+func resolveTrailers(trailers map[string]string, body []byte) map[string]string {
+	for name, value := range trailers {
+		if value == constants.TrailerValueSHA256 {
+			trailers[name] = fmt.Sprintf("%x", sha256.Sum256(body))
+		}
+	}
+	return trailers
 }
 
 // parseStatusCode converts string status code to int with fallback
@@ -156,32 +361,135 @@ func parseStatusCode(code string) int {
 	return statusCode
 }
 
-// sendJSONResponse sends a JSON response with the specified status code
-func (s *Server) sendJSONResponse(w http.ResponseWriter, statusCode int, body []byte) {
-	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+// sendMockResponse sends a generated response, followed by its declared trailers
+func (s *Server) sendMockResponse(w http.ResponseWriter, response cachedResponse) {
+	names := make([]string, 0, len(response.Trailers))
+	for name := range response.Trailers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		w.Header().Add(constants.HeaderTrailer, name)
+	}
+
+	s.sendJSONResponse(w, response.StatusCode, response.ContentType, response.Body)
+
+	for _, name := range names {
+		w.Header().Set(name, response.Trailers[name])
+	}
+}
+
+// sendJSONResponse sends a response with the specified status code and content
+// type, defaulting to JSON
+func (s *Server) sendJSONResponse(w http.ResponseWriter, statusCode int, contentType string, body []byte) {
+	if s.config != nil && s.config.Server.Collapse2xx && statusCode >= 200 && statusCode < 300 {
+		statusCode = http.StatusOK
+	}
+	if len(body) == 0 {
+		w.WriteHeader(statusCode)
+		return
+	}
+	if contentType == "" {
+		contentType = constants.ContentTypeJSON
+	}
+	w.Header().Set(constants.HeaderContentType, contentType)
 	w.WriteHeader(statusCode)
 	_, _ = w.Write(body)
 }
 
 // sendErrorResponse sends a JSON error response
-func (s *Server) sendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+func (s *Server) sendErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	if s.sendFormattedError(w, r, statusCode, message, nil) {
+		return
+	}
 	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
 	w.WriteHeader(statusCode)
 	response := map[string]string{"error": message}
 	_ = json.NewEncoder(w).Encode(response)
 }
 
-// sendMethodNotAllowedResponse sends a 405 Method Not Allowed response
-func (s *Server) sendMethodNotAllowedResponse(w http.ResponseWriter, methods []string, requestedMethod string) {
+// sendMaintenanceResponse sends the configured maintenance response with a Retry-After hint
+func (s *Server) sendMaintenanceResponse(w http.ResponseWriter, r *http.Request) {
+	maintenance := s.config.Maintenance
+
+	statusCode := maintenance.StatusCode
+	if statusCode == 0 {
+		statusCode = constants.StatusServiceUnavailable
+	}
+	if maintenance.RetryAfter > 0 {
+		retryAfter := int(math.Ceil(maintenance.RetryAfter.Seconds()))
+		w.Header().Set(constants.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	}
+
+	message := maintenance.Message
+	if message == "" {
+		message = "Service is under maintenance"
+	}
+	s.sendErrorResponse(w, r, statusCode, message)
+}
+
+// sendMethodNotAllowedResponse sends a 405 Method Not Allowed response, listing
+// the allowed methods in the Allow header and the body
+func (s *Server) sendMethodNotAllowedResponse(w http.ResponseWriter, r *http.Request, methods []string) {
+	w.Header().Set(constants.HeaderAllow, strings.Join(methods, ", "))
+	message := fmt.Sprintf("Method %s not allowed", r.Method)
+	if s.sendFormattedError(w, r, constants.StatusMethodNotAllowed, message, methods) {
+		return
+	}
 	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
 	w.WriteHeader(constants.StatusMethodNotAllowed)
 	response := map[string]interface{}{
-		"error":   fmt.Sprintf("Method %s not allowed", requestedMethod),
+		"error":   message,
 		"methods": methods,
 	}
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// sendNotFoundResponse sends a 404 for requests that match no route, using
+// the configured error format when there is one
+func (s *Server) sendNotFoundResponse(w http.ResponseWriter, r *http.Request) {
+	if s.sendFormattedError(w, r, http.StatusNotFound, "Not found", nil) {
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// problemDetails is an RFC 7807 problem+json error body. Methods is an
+// extension member listing the allowed methods of a 405.
+type problemDetails struct {
+	Type     string   `json:"type"`
+	Title    string   `json:"title"`
+	Status   int      `json:"status"`
+	Detail   string   `json:"detail"`
+	Instance string   `json:"instance"`
+	Methods  []string `json:"methods,omitempty"`
+}
+
+// sendFormattedError renders server.error_format, reporting false when no
+// format is configured
+func (s *Server) sendFormattedError(w http.ResponseWriter, r *http.Request, statusCode int, message string, methods []string) bool {
+	if s.config == nil || s.config.Server.ErrorFormat == "" {
+		return false
+	}
+	if s.config.Server.ErrorFormat == config.ErrorFormatProblemJSON {
+		w.Header().Set(constants.HeaderContentType, constants.ContentTypeProblemJSON)
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(problemDetails{
+			Type:     "about:blank",
+			Title:    http.StatusText(statusCode),
+			Status:   statusCode,
+			Detail:   message,
+			Instance: r.URL.Path,
+			Methods:  methods,
+		})
+		return true
+	}
+	w.Header().Set(constants.HeaderContentType, constants.ContentTypeJSON)
+	w.WriteHeader(statusCode)
+	_, _ = io.WriteString(w, config.RenderErrorFormat(s.config.Server.ErrorFormat, statusCode, message, methods))
+	return true
+}
+
 // ResponseWriter wraps http.ResponseWriter to capture status code for logging
 type ResponseWriter struct {
 	http.ResponseWriter
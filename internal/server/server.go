@@ -2,8 +2,11 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,6 +20,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/leslieo2/go-spec-mock/internal/config"
 	"github.com/leslieo2/go-spec-mock/internal/constants"
+	"github.com/leslieo2/go-spec-mock/internal/generator"
 	"github.com/leslieo2/go-spec-mock/internal/observability"
 	"github.com/leslieo2/go-spec-mock/internal/parser"
 	"github.com/leslieo2/go-spec-mock/internal/server/middleware"
@@ -49,84 +53,308 @@ type Server struct {
 	parser   *parser.Parser
 	config   *config.Config
 	server   *http.Server
-	cache    *sync.Map
+	cache    *responseCache
 	routes   []parser.Route
 	routeMap map[string][]parser.Route
 	mu       sync.RWMutex // Protects routes, routeMap, and parser
 
+	// Cancels the base context of in-flight requests on shutdown
+	cancelRequests context.CancelFunc
+
 	// Dynamic handler for hot reload
 	dynamicHandler *DynamicHandler
 
 	// Observability
 	logger    *observability.Logger
+	tracer    *observability.Tracer // nil when tracing is disabled
 	startTime time.Time
 
 	// Proxy
-	proxy *middleware.Proxy
+	proxy    *middleware.Proxy
+	recorder *middleware.Recorder // nil unless recording in live mode
+	replayer *middleware.Replayer // nil unless proxy.mode is replay
+
+	// Unix nanoseconds of the first reload failure since the spec was last
+	// loaded successfully; 0 while the served spec is current
+	staleSince atomic.Int64
+
+	// Runtime-toggled maintenance mode and disabled operations
+	maintenance atomic.Bool
+	outages     *outageSet
+
+	// Response cache lookups, reported by the stats endpoint
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+
+	// Per-response counters for round-robin example rotation
+	exampleCounters sync.Map // map[string]*atomic.Uint64
+
+	// Accept-Language negotiation; nil when no languages are configured
+	languages *languageMatcher
+
+	// First responses per Idempotency-Key; nil when idempotency is disabled
+	idempotency *idempotencyStore
+
+	// Simulated cookie sessions; nil when sessions are disabled
+	sessions *sessionStore
 }
 
 func New(cfg *config.Config) (*Server, error) {
-	p, err := parser.New(cfg.SpecFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
-	}
-
 	// Initialize observability
 	logger, err := observability.NewLogger(cfg.Observability.Logging)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	p, err := LoadParser(cfg, logger.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	var tracer *observability.Tracer
+	if cfg.Observability.Tracing.Enabled {
+		tracer, err = observability.NewTracer(cfg.Observability.Tracing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+		}
+	}
+
+	var recorder *middleware.Recorder
+	var replayer *middleware.Replayer
+	switch {
+	case !cfg.Proxy.Enabled:
+	case cfg.Proxy.Replaying():
+		replayer, err = middleware.NewReplayer(cfg.Proxy.RecordDir, cfg.Proxy.ReplayMatchBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load proxy recordings: %w", err)
+		}
+	case cfg.Proxy.RecordDir != "":
+		recorder, err = middleware.NewRecorder(cfg.Proxy.RecordDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize proxy recording: %w", err)
+		}
+	}
+
+	warmupCache(cfg, p, logger.Logger)
+
 	// Pre-build routes and route map
 	routes := p.GetRoutes()
-	routeMap := make(map[string][]parser.Route)
-	for _, route := range routes {
-		routeMap[route.Path] = append(routeMap[route.Path], route)
+	if err := checkRoutes(cfg, routes, logger.Logger); err != nil {
+		return nil, err
 	}
+	routeMap := buildRouteMap(routes, routeBasePaths(cfg, p))
 
-	return &Server{
+	s := &Server{
 		parser:   p,
 		config:   cfg,
-		cache:    &sync.Map{},
+		cache:    newResponseCache(cfg.Cache.EntryLimit()),
 		routes:   routes,
 		routeMap: routeMap,
 		logger:   logger,
+		tracer:   tracer,
+		recorder: recorder,
+		replayer: replayer,
+		outages:  newOutageSet(cfg.Outages.Operations),
+
+		idempotency: newIdempotencyStore(cfg.Idempotency),
+		sessions:    newSessionStore(cfg.Sessions),
+		languages:   newLanguageMatcher(cfg.Language),
 
 		startTime: time.Now(),
-	}, nil
+	}
+	s.maintenance.Store(cfg.Maintenance.Enabled)
+
+	return s, nil
+}
+
+// warmupRemaining returns how much of the configured warmup delay is left
+func (s *Server) warmupRemaining() time.Duration {
+	if s.config == nil {
+		return 0
+	}
+	return s.config.Server.WarmupDelay - time.Since(s.startTime)
+}
+
+// LoadParser parses the configured spec and applies the generator configuration
+func LoadParser(cfg *config.Config, logger *zap.Logger) (*parser.Parser, error) {
+	p, err := parser.NewWithOptions(cfg.SpecFile, parser.Options{
+		ExpandEnv:     cfg.ExpandEnv,
+		StrictEnv:     cfg.StrictEnv,
+		PropertyOrder: cfg.Response.PreserveOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.SetGeneratorConfig(generator.Config{
+		UseFieldNameForData:    true,
+		DefaultArrayLength:     cfg.Generator.DefaultArrayLength,
+		MaxArrayLength:         cfg.Generator.MaxArrayLength,
+		MapEntries:             cfg.Generator.MapEntries,
+		NullProbability:        cfg.Generator.NullChance(),
+		BooleanTrueProbability: cfg.Generator.BooleanTrueProbability,
+		StableUUIDs:            cfg.Generator.StableUUIDs,
+		Logger:                 logger,
+	})
+	return p, nil
+}
+
+// warmupCache generates every defined response ahead of the first request when
+// cache.warmup is set and caching is enabled
+func warmupCache(cfg *config.Config, p *parser.Parser, logger *zap.Logger) {
+	if !cfg.Cache.Warmup || !cfg.Cache.IsEnabled() {
+		return
+	}
+	start := time.Now()
+	entries := p.Warmup()
+	logger.Info("Response cache warmed up",
+		zap.Int("entries", entries),
+		zap.Duration("duration", time.Since(start)),
+	)
+}
+
+// checkRoutes reports a spec that defines no operations, where every request
+// would get 404: a warning by default, and an error with strict. Specs used
+// only to proxy are expected to be empty.
+func checkRoutes(cfg *config.Config, routes []parser.Route, logger *zap.Logger) error {
+	if len(routes) > 0 || cfg.Proxy.Enabled {
+		return nil
+	}
+	if cfg.Strict {
+		return fmt.Errorf("OpenAPI spec %s defines no operations", cfg.SpecFile)
+	}
+	logger.Warn("OpenAPI spec defines no operations; every request will get 404",
+		zap.String("spec_file", cfg.SpecFile),
+	)
+	return nil
+}
+
+// routeBasePaths returns the prefixes mock routes are registered under: the
+// spec's server base paths with server.use_server_base_path, otherwise none
+func routeBasePaths(cfg *config.Config, p *parser.Parser) []string {
+	if cfg.Server.UseServerBasePath {
+		if basePaths := p.ServerBasePaths(); len(basePaths) > 0 {
+			return basePaths
+		}
+	}
+	return []string{""}
+}
+
+// buildRouteMap groups routes by the path they are served at, registering each
+// spec path once under every base path
+func buildRouteMap(routes []parser.Route, basePaths []string) map[string][]parser.Route {
+	routeMap := make(map[string][]parser.Route)
+	for _, basePath := range basePaths {
+		for _, route := range routes {
+			routeMap[basePath+route.Path] = append(routeMap[basePath+route.Path], route)
+		}
+	}
+	return routeMap
 }
 
 // setupMiddleware applies all middleware to the router
 func (s *Server) setupMiddleware(router *chi.Mux) {
+	// Request ID middleware, ahead of logging so every log line can be correlated
+	router.Use(middleware.RequestIDMiddleware())
+	// Tracing middleware, only registered when tracing is enabled
+	if s.tracer != nil {
+		router.Use(middleware.TracingMiddleware(s.tracer))
+	}
+	// Method override middleware, ahead of logging and routing so both see the effective method
+	if s.config.Server.MethodOverride {
+		router.Use(middleware.MethodOverrideMiddleware(s.logger.Logger))
+	}
 	// Logging middleware
 	router.Use(middleware.LoggingMiddleware(s.logger.Logger))
+	// Response compression middleware
+	if s.config.Server.Compression {
+		router.Use(middleware.CompressionMiddleware(s.config.Server.CompressionLevel))
+	}
+	// Query length limit middleware
+	router.Use(middleware.QueryLengthLimitMiddleware(s.config.Server.QueryLengthLimit(), s.logger.Logger))
 	// Delay simulation middleware
-	router.Use(middleware.DelayMiddleware(s.logger.Logger))
+	router.Use(middleware.DelayMiddleware(s.config.Server.ResponseDelay, s.logger.Logger))
 	// Status code extraction middleware
 	router.Use(middleware.StatusCodeMiddleware(s.logger.Logger))
 	// Example name selection middleware
 	router.Use(middleware.ExampleMiddleware(s.logger.Logger))
 	// Request size limit middleware
-	router.Use(middleware.RequestSizeLimitMiddleware(constants.ServerMaxRequestSize, s.logger.Logger))
+	router.Use(middleware.RequestSizeLimitMiddleware(s.maxRequestSize(), s.logger.Logger))
+	// Path prefix rewriting, ahead of lenient matching and CORS so both see the routed path
+	if s.config.Server.StripPathPrefix != "" || s.config.Server.AddPathPrefix != "" {
+		router.Use(s.pathPrefixMiddleware(router))
+	}
+	// Lenient path matching, ahead of CORS so that route overrides see the matched path
+	if !s.config.Server.IsStrictSlash() || s.config.Server.CaseInsensitivePaths {
+		router.Use(s.pathNormalizationMiddleware(router))
+	}
 	// CORS middleware
 	if s.config.Security.CORS.Enabled {
-		corsMiddleware := middleware.NewCORSMiddleware(
-			s.config.Security.CORS.AllowedOrigins,
-			s.config.Security.CORS.AllowedMethods,
-			s.config.Security.CORS.AllowedHeaders,
-			s.config.Security.CORS.AllowCredentials,
-			s.config.Security.CORS.MaxAge,
-			s.logger.Logger,
-		)
+		cors := s.config.Security.CORS
+		corsMiddleware := newCORSMiddleware(cors, s.logger.Logger)
+		if len(cors.Routes) > 0 {
+			s.mu.RLock()
+			basePaths := routeBasePaths(s.config, s.parser)
+			s.mu.RUnlock()
+			routes := make(map[string]*middleware.CORSMiddleware, len(cors.Routes)*len(basePaths))
+			for path := range cors.Routes {
+				routeCORS := newCORSMiddleware(cors.ForRoute(path), s.logger.Logger)
+				for _, basePath := range basePaths {
+					routes[basePath+path] = routeCORS
+				}
+			}
+			corsMiddleware.WithRoutes(routes, func(r *http.Request) string {
+				return routePattern(router, r)
+			})
+		}
 		router.Use(corsMiddleware.Handler)
 	}
 }
 
+// newCORSMiddleware creates the CORS middleware for a set of CORS settings
+func newCORSMiddleware(cors config.CORSConfig, logger *zap.Logger) *middleware.CORSMiddleware {
+	return middleware.NewCORSMiddleware(
+		cors.AllowedOrigins,
+		cors.AllowedMethods,
+		cors.AllowedHeaders,
+		cors.AllowCredentials,
+		cors.PreflightMaxAge(),
+		cors.PreflightStatus,
+		logger,
+	)
+}
+
+// routePattern returns the pattern of the route the router will match for the
+// request, or "" when none does. Preflight requests are matched with the method
+// they ask about, since OPTIONS itself is rarely routed.
+func routePattern(router *chi.Mux, r *http.Request) string {
+	method := r.Method
+	if method == http.MethodOptions {
+		if requested := r.Header.Get(constants.HeaderAccessControlRequestMethod); requested != "" {
+			method = requested
+		}
+	}
+	rctx := chi.NewRouteContext()
+	if !router.Match(rctx, method, r.URL.Path) {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
+
 // registerSpecialRoutes registers health, ready, documentation, and root redirect routes
 func (s *Server) registerSpecialRoutes(router *chi.Mux) {
 	router.Get(constants.PathHealth, s.healthHandler)
 	router.Get(constants.PathReady, s.readinessHandler)
 	router.Get(constants.PathDocumentation, s.serveDocumentation)
+	router.Get(constants.PathOpenAPIJSON, s.serveOpenAPIJSON)
+	router.Get(constants.PathOpenAPIYAML, s.serveOpenAPIYAML)
+	// Registered last so that a raw_spec_path of /openapi.yaml takes precedence
+	if s.config.Server.RawSpecPath != "" {
+		router.Get(s.config.Server.RawSpecPath, s.serveRawSpec)
+	}
+	if s.config.Admin.Enabled {
+		s.registerAdminRoutes(router)
+	}
 	// Handle root path redirect separately
 	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		// If the spec defines a "/" route, it will be handled below.
@@ -158,9 +386,14 @@ func (s *Server) registerMockRoutes(router *chi.Mux) {
 		}
 
 		// Register this handler for all methods defined for this path
-		for _, route := range currentRoutes {
+		methods := make(map[string]*parser.Route, len(currentRoutes))
+		for i, route := range currentRoutes {
 			// chi router methods are uppercase (GET, POST, etc.)
 			router.Method(strings.ToUpper(route.Method), path, http.HandlerFunc(handler))
+			methods[route.Method] = &currentRoutes[i]
+		}
+		if _, ok := s.headFallback(methods); ok {
+			router.Method(http.MethodHead, path, http.HandlerFunc(handler))
 		}
 	}
 }
@@ -173,9 +406,26 @@ func (s *Server) setupFallbackHandler(router *chi.Mux) {
 			s.logger.Logger.Debug("No mock route found, proxying request", zap.String("path", r.URL.Path))
 			s.handleProxyRequest(w, r)
 		} else {
-			http.NotFound(w, r)
+			s.sendNotFoundResponse(w, r)
 		}
 	})
+
+	// Called when the path exists in the spec but not for this method
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		methods := make([]string, 0, len(allowedMethodCandidates))
+		for _, method := range allowedMethodCandidates {
+			if router.Match(chi.NewRouteContext(), method, r.URL.Path) {
+				methods = append(methods, method)
+			}
+		}
+		s.sendMethodNotAllowedResponse(w, r, methods)
+	})
+}
+
+// allowedMethodCandidates lists the methods reported in 405 responses, in order
+var allowedMethodCandidates = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
 }
 
 // buildHandler creates a new http.Handler using the chi router for proper path parameter support.
@@ -200,6 +450,26 @@ func (s *Server) buildHandler() http.Handler {
 // handleMockRequest handles mock requests for a specific path with multiple routes
 func (s *Server) handleMockRequest(w http.ResponseWriter, r *http.Request, routes []parser.Route) {
 	start := time.Now()
+	logger := s.logger.Logger.With(zap.String("request_id", middleware.GetRequestIDFromContext(r)))
+
+	if s.maintenance.Load() {
+		s.sendMaintenanceResponse(w, r)
+		logger.Debug("Rejected request during maintenance",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		return
+	}
+
+	if remaining := s.warmupRemaining(); remaining > 0 {
+		w.Header().Set(constants.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+		s.sendErrorResponse(w, r, constants.StatusServiceUnavailable, "Service is warming up")
+		logger.Debug("Rejected request during warmup",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		return
+	}
 
 	// Get request size
 	requestSize := r.ContentLength
@@ -221,9 +491,17 @@ func (s *Server) handleMockRequest(w http.ResponseWriter, r *http.Request, route
 
 	// Fast path: check if method exists
 	matchedRoute, exists := routeLookup[r.Method]
+	if !exists && r.Method == http.MethodHead {
+		if getRoute, ok := s.headFallback(routeLookup); ok {
+			matchedRoute, exists = getRoute, true
+			hw := &headResponseWriter{ResponseWriter: w}
+			defer hw.finish()
+			w = hw
+		}
+	}
 	if !exists {
-		s.sendMethodNotAllowedResponse(w, methods, r.Method)
-		s.logger.Logger.Warn("Method not allowed",
+		s.sendMethodNotAllowedResponse(w, r, methods)
+		logger.Warn("Method not allowed",
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
 			zap.String("remote_addr", r.RemoteAddr),
@@ -231,54 +509,135 @@ func (s *Server) handleMockRequest(w http.ResponseWriter, r *http.Request, route
 		return
 	}
 
-	// Generate response - get status code and example name from context or use defaults
-	statusCodeStr := getStatusCodeFromContext(r)
-	exampleName := middleware.GetExampleNameFromContext(r)
+	if parser.OperationUnimplemented(matchedRoute.Operation) {
+		s.sendErrorResponse(w, r, http.StatusNotImplemented, fmt.Sprintf("Operation %s %s is not implemented yet", r.Method, matchedRoute.Path))
+		logger.Debug("Rejected request to unimplemented operation",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		return
+	}
 
-	// Cache key for response
-	cacheKey := s.generateCacheKey(r.Method, r.URL.Path, r, statusCodeStr, exampleName)
+	if !s.enforceRouteRequestSize(w, r, matchedRoute, logger) {
+		return
+	}
+
+	if s.outages.matches(matchedRoute) {
+		s.sendErrorResponse(w, r, s.outageStatusCode(), fmt.Sprintf("Operation %s %s is unavailable", r.Method, matchedRoute.Path))
+		logger.Debug("Rejected request to disabled operation",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		return
+	}
+
+	if s.sessions != nil && !s.sessions.handle(w, r, matchedRoute) {
+		s.sendErrorResponse(w, r, http.StatusUnauthorized, "A valid session cookie is required")
+		logger.Debug("Rejected request without a session",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		return
+	}
+
+	if !s.applyOperationDelay(r, matchedRoute, logger) {
+		return
+	}
+
+	if s.echoEnabled(r) {
+		s.sendEchoResponse(w, r)
+		logger.Debug("Echoed request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+		)
+		return
+	}
 
-	// Try to get from cache
-	if cached, ok := s.getCachedResponse(cacheKey); ok {
-		s.sendJSONResponse(w, cached.StatusCode, cached.Body)
-		s.logger.Logger.Debug("Served from cache",
+	if body, ok := s.responseOverride(r); ok {
+		s.sendResponseOverride(w, r, body)
+		logger.Debug("Served overridden response",
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
-			zap.Int("status_code", cached.StatusCode),
 		)
 		return
 	}
-	buf, status, err := s.generateResponse(matchedRoute, statusCodeStr, exampleName)
+
+	// Generate response - get status code and example name from context or use defaults
+	statusCodeStr := getStatusCodeFromContext(r)
+	exampleName := s.selectExampleName(r, matchedRoute, statusCodeStr)
+
+	// Repeated Idempotency-Key requests replay the first response
+	idempotencyKey := s.idempotencyKey(r)
+	if idempotencyKey != "" {
+		if stored, ok := s.idempotency.get(idempotencyKey); ok {
+			w.Header().Set(constants.HeaderIdempotentReplayed, "true")
+			s.sendMockResponse(w, stored)
+			logger.Debug("Replayed idempotent response",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status_code", stored.StatusCode),
+			)
+			return
+		}
+	}
+
+	// Cache key for response
+	cacheKey := s.generateCacheKey(r.Method, r.URL.Path, r, statusCodeStr, exampleName)
+
+	// Idempotent and uncached requests get a freshly generated response
+	useCache := idempotencyKey == "" && s.cachingEnabled(r, matchedRoute)
+	if useCache {
+		if cached, ok := s.getCachedResponse(cacheKey); ok {
+			s.setCacheDebugHeaders(w, cacheKey, true)
+			s.sendMockResponse(w, *cached)
+			logger.Debug("Served from cache",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status_code", cached.StatusCode),
+			)
+			return
+		}
+	}
+	generationStart := time.Now()
+	response, err := s.generateResponse(r, matchedRoute, statusCodeStr, exampleName, !useCache)
 	if err != nil {
 		if strings.Contains(err.Error(), "no example found") {
-			s.sendErrorResponse(w, http.StatusNotFound, err.Error())
+			s.sendErrorResponse(w, r, http.StatusNotFound, err.Error())
 
-			s.logger.Logger.Warn("No example found",
+			logger.Warn("No example found",
 				zap.String("status_code", statusCodeStr),
 				zap.String("path", r.URL.Path),
 			)
 		} else {
-			s.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
+			s.sendErrorResponse(w, r, http.StatusInternalServerError, err.Error())
 
-			s.logger.Logger.Error("Failed to serialize response",
+			logger.Error("Failed to serialize response",
 				zap.Error(err),
 				zap.String("path", r.URL.Path),
 			)
 		}
 		return
 	}
+	if s.exceedsGenerationTimeout(w, r, time.Since(generationStart), logger) {
+		return
+	}
 
-	responseSize := int64(len(buf))
+	responseSize := int64(len(response.Body))
 
-	// Cache the response
-	s.cacheResponse(cacheKey, status, buf)
+	if idempotencyKey != "" {
+		response = s.idempotency.putIfAbsent(idempotencyKey, response)
+	} else if useCache {
+		// Cache the response
+		s.cacheResponse(cacheKey, response)
+		s.setCacheDebugHeaders(w, cacheKey, false)
+	}
 
 	// Send response
-	s.sendJSONResponse(w, status, buf)
-	s.logger.Logger.Debug("Request processed",
+	s.sendMockResponse(w, response)
+	logger.Debug("Request processed",
 		zap.String("method", r.Method),
 		zap.String("path", r.URL.Path),
-		zap.Int("status_code", status),
+		zap.Int("status_code", response.StatusCode),
 		zap.Duration("duration", time.Since(start)),
 		zap.Int64("request_size", requestSize),
 		zap.Int64("response_size", responseSize),
@@ -293,13 +652,18 @@ func (s *Server) Start() error {
 	// Apply middleware chain to the dynamic handler
 	handler := s.dynamicHandler
 
-	s.server = &http.Server{
-		Addr:           fmt.Sprintf("%s:%s", s.config.Server.Host, s.config.Server.Port),
-		Handler:        handler,
-		ReadTimeout:    constants.ServerReadTimeout,
-		WriteTimeout:   constants.ServerWriteTimeout,
-		IdleTimeout:    constants.ServerIdleTimeout,
-		MaxHeaderBytes: 1 << 20, // 1MB max header size
+	s.server = s.newHTTPServer(handler)
+
+	if s.config.TLS.Enabled && s.config.TLS.SelfSigned() {
+		cert, err := generateSelfSignedCertificate(s.config.Server.Host)
+		if err != nil {
+			return fmt.Errorf("failed to generate self-signed certificate: %w", err)
+		}
+		s.server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		s.logger.Logger.Warn("No TLS certificate configured, using a generated self-signed certificate")
 	}
 
 	s.logger.Logger.Info("Starting server",
@@ -331,33 +695,109 @@ func (s *Server) Start() error {
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	sig := <-quit
 
-	s.logger.Logger.Info("Shutting down server...")
+	timeout := shutdownTimeout(sig, s.config.Server.ShutdownTimeout)
+	s.logger.Logger.Info("Shutting down server...",
+		zap.String("signal", sig.String()),
+		zap.Duration("timeout", timeout),
+	)
 
 	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), constants.ServerShutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	s.logger.Logger.Info("Shutting down main server...")
-	if err := s.server.Shutdown(ctx); err != nil {
+	if err := s.drain(ctx); err != nil {
 		s.logger.Logger.Error("Failed to shutdown main server", zap.Error(err))
 		return fmt.Errorf("main server shutdown: %w", err)
 	}
 
+	if err := s.shutdownTracer(ctx); err != nil {
+		s.logger.Logger.Error("Failed to flush traces", zap.Error(err))
+	}
+
 	return nil
 }
 
+// newHTTPServer creates the HTTP server for handler. Requests run under a base
+// context that drain cancels, so handlers waiting on their context stop once
+// the shutdown timeout has passed.
+func (s *Server) newHTTPServer(handler http.Handler) *http.Server {
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancelRequests = cancel
+
+	server := &http.Server{
+		Addr:           fmt.Sprintf("%s:%s", s.config.Server.Host, s.config.Server.Port),
+		Handler:        handler,
+		ReadTimeout:    serverTimeout(s.config.Server.ReadTimeout, constants.ServerReadTimeout),
+		WriteTimeout:   serverTimeout(s.config.Server.WriteTimeout, constants.ServerWriteTimeout),
+		IdleTimeout:    serverTimeout(s.config.Server.IdleTimeout, constants.ServerIdleTimeout),
+		MaxHeaderBytes: 1 << 20, // 1MB max header size
+		BaseContext:    func(net.Listener) context.Context { return baseCtx },
+	}
+	if s.config.Server.DisableKeepAlives {
+		server.SetKeepAlivesEnabled(false)
+	}
+	return server
+}
+
+// serverTimeout returns the configured timeout, or fallback when none is set
+func serverTimeout(configured, fallback time.Duration) time.Duration {
+	if configured <= 0 {
+		return fallback
+	}
+	return configured
+}
+
+// drain stops accepting connections and waits for in-flight requests until ctx
+// is done, then cancels the requests that are still running
+func (s *Server) drain(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if s.cancelRequests != nil {
+		s.cancelRequests()
+	}
+	return err
+}
+
+// shutdownTimeout returns how long in-flight requests may drain after sig.
+// SIGTERM comes from orchestrators and gets the configured timeout; SIGINT is
+// usually Ctrl-C during development and shuts down quickly.
+func shutdownTimeout(sig os.Signal, configured time.Duration) time.Duration {
+	if configured <= 0 {
+		configured = constants.ServerShutdownTimeout
+	}
+	if sig == os.Interrupt && configured > constants.ServerInterruptShutdownTimeout {
+		return constants.ServerInterruptShutdownTimeout
+	}
+	return configured
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
 	if s.server == nil {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), constants.ServerShutdownTimeout)
+	var configured time.Duration
+	if s.config != nil {
+		configured = s.config.Server.ShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout(syscall.SIGTERM, configured))
 	defer cancel()
 
-	return s.server.Shutdown(ctx)
+	if err := s.drain(ctx); err != nil {
+		return err
+	}
+	return s.shutdownTracer(ctx)
+}
+
+// shutdownTracer flushes pending spans when tracing is enabled
+func (s *Server) shutdownTracer(ctx context.Context) error {
+	if s.tracer == nil {
+		return nil
+	}
+	return s.tracer.Shutdown(ctx)
 }
 
 // Reload implements the hotreload.Reloadable interface
@@ -365,18 +805,22 @@ func (s *Server) Reload(ctx context.Context) error {
 	s.logger.Logger.Info("Reloading server configuration - Reload method called!")
 
 	// Parse the updated OpenAPI spec
-	newParser, err := parser.New(s.config.SpecFile)
+	newParser, err := LoadParser(s.config, s.logger.Logger)
 	if err != nil {
+		// Keep serving the last good spec, but remember how long it has been stale
+		s.staleSince.CompareAndSwap(0, time.Now().UnixNano())
 		return fmt.Errorf("failed to parse updated OpenAPI spec: %w", err)
 	}
+	s.staleSince.Store(0)
+	warmupCache(s.config, newParser, s.logger.Logger)
 
 	// Update routes by re-initializing the parser and routes
 	newRoutes := newParser.GetRoutes()
-	newRouteMap := make(map[string][]parser.Route)
-
-	for _, route := range newRoutes {
-		newRouteMap[route.Path] = append(newRouteMap[route.Path], route)
+	if err := checkRoutes(s.config, newRoutes, s.logger.Logger); err != nil {
+		s.staleSince.CompareAndSwap(0, time.Now().UnixNano())
+		return err
 	}
+	newRouteMap := buildRouteMap(newRoutes, routeBasePaths(s.config, newParser))
 
 	// Update server state atomically with proper synchronization
 	s.mu.Lock()
@@ -388,9 +832,10 @@ func (s *Server) Reload(ctx context.Context) error {
 	// Clear the cache to ensure new responses are generated from the updated spec
 	s.clearCache()
 
-	// Rebuild and swap the handler atomically
-	newHandler := s.buildHandler()
-	s.dynamicHandler.UpdateHandler(newHandler)
+	// Rebuild and swap the handler atomically; there is none before Start
+	if s.dynamicHandler != nil {
+		s.dynamicHandler.UpdateHandler(s.buildHandler())
+	}
 
 	s.logger.Logger.Info("Server configuration reloaded successfully",
 		zap.Int("routes", len(newRoutes)))
@@ -399,6 +844,35 @@ func (s *Server) Reload(ctx context.Context) error {
 
 // handleProxyRequest handles requests by forwarding them to the configured proxy target
 func (s *Server) handleProxyRequest(w http.ResponseWriter, r *http.Request) {
+	if !s.config.Proxy.Forwards(r.URL.Path) {
+		s.logger.Logger.Debug("Path outside proxy prefixes, not proxying", zap.String("path", r.URL.Path))
+		s.sendNotFoundResponse(w, r)
+		return
+	}
+
+	// Body hashes are only needed for recording or body-matched replay
+	var bodyHash string
+	if s.recorder != nil || (s.replayer != nil && s.replayer.MatchesBody()) {
+		hash, err := middleware.HashRequestBody(r)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		bodyHash = hash
+	}
+
+	if s.replayer != nil {
+		if recording, ok := s.replayer.Lookup(r, bodyHash); ok {
+			recording.Replay(w)
+			return
+		}
+		if !s.config.Proxy.ReplayFallback {
+			s.logger.Logger.Debug("No recording for request", zap.String("method", r.Method), zap.String("path", r.URL.Path))
+			s.sendNotFoundResponse(w, r)
+			return
+		}
+	}
+
 	if s.proxy == nil {
 		// Lazy initialization of proxy
 		proxy, err := middleware.NewProxy(s.config.Proxy)
@@ -410,8 +884,18 @@ func (s *Server) handleProxyRequest(w http.ResponseWriter, r *http.Request) {
 		s.proxy = proxy
 	}
 
-	// Forward the request to the target server
-	s.proxy.ServeHTTP(w, r)
+	if s.recorder == nil {
+		// Forward the request to the target server
+		s.proxy.ServeHTTP(w, r)
+		return
+	}
+
+	// Forward the request and keep a copy of the response for the recording
+	rw := middleware.NewRecordingResponseWriter(w)
+	s.proxy.ServeHTTP(rw, r)
+	if err := s.recorder.Record(r, bodyHash, rw); err != nil {
+		s.logger.Logger.Error("Failed to record proxied response", zap.Error(err), zap.String("path", r.URL.Path))
+	}
 }
 
 // Name returns the name of this reloadable component
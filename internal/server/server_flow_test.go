@@ -1,16 +1,28 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/server/middleware"
+	"github.com/leslieo2/go-spec-mock/internal/version"
+	"gopkg.in/yaml.v3"
 )
 
 func TestServerServesGeneratedResponse(t *testing.T) {
@@ -83,6 +95,1385 @@ paths:
 	}
 }
 
+func TestServerServesResponsesWithoutContent(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Bodiless API
+  version: 1.0.0
+paths:
+  /items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    delete:
+      operationId: deleteItem
+      responses:
+        "204":
+          description: Item deleted
+    put:
+      operationId: replaceItem
+      responses:
+        "200":
+          description: Item replaced
+          content:
+            application/json: {}
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	// Serve twice so the cached response path is exercised as well
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodDelete, "/items/1", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Fatalf("expected empty body, got %q", rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "" {
+			t.Fatalf("expected no content-type, got %s", ct)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/items/1", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if body := rec.Body.String(); body != "{}" {
+		t.Fatalf("expected empty JSON object, got %q", body)
+	}
+}
+
+func TestServerServesMultipartResponses(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Downloads API
+  version: 1.0.0
+paths:
+  /downloads:
+    get:
+      operationId: listDownloads
+      responses:
+        "200":
+          description: Metadata and thumbnail
+          content:
+            multipart/form-data:
+              schema:
+                type: object
+                properties:
+                  metadata:
+                    type: object
+                    properties:
+                      title:
+                        type: string
+                  count:
+                    type: integer
+                  thumbnail:
+                    type: string
+                    format: binary
+              encoding:
+                thumbnail:
+                  contentType: image/png
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/downloads", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		t.Fatalf("expected multipart/form-data with a boundary, got %q", rec.Header().Get("Content-Type"))
+	}
+
+	parts := make(map[string]*multipart.Part)
+	contents := make(map[string][]byte)
+	reader := multipart.NewReader(rec.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read part %s: %v", part.FormName(), err)
+		}
+		parts[part.FormName()] = part
+		contents[part.FormName()] = data
+	}
+
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+
+	if ct := parts["metadata"].Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON metadata part, got %s", ct)
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal(contents["metadata"], &metadata); err != nil {
+		t.Errorf("expected metadata part to be JSON: %v", err)
+	}
+
+	if ct := parts["count"].Header.Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("expected text count part, got %s", ct)
+	}
+
+	thumbnail := parts["thumbnail"]
+	if ct := thumbnail.Header.Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected thumbnail content type from encoding, got %s", ct)
+	}
+	if thumbnail.FileName() == "" {
+		t.Error("expected binary thumbnail part to have a filename")
+	}
+}
+
+func TestServerServesBinaryResponses(t *testing.T) {
+	const pngBase64 = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+	spec := `openapi: 3.0.0
+info:
+  title: Images API
+  version: 1.0.0
+paths:
+  /avatar:
+    get:
+      operationId: getAvatar
+      responses:
+        "200":
+          description: Avatar image
+          content:
+            image/png:
+              schema:
+                type: string
+                format: binary
+              example: ` + pngBase64 + `
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/avatar", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected Content-Type image/png, got %s", ct)
+	}
+
+	want, _ := base64.StdEncoding.DecodeString(pngBase64)
+	if !bytes.Equal(rec.Body.Bytes(), want) {
+		t.Errorf("expected the decoded PNG bytes, got %q", rec.Body.String())
+	}
+	if !bytes.HasPrefix(rec.Body.Bytes(), []byte("\x89PNG")) {
+		t.Error("expected the body to start with the PNG signature")
+	}
+}
+
+func TestServerUsesDeclaredJSONMediaType(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Articles API
+  version: 1.0.0
+paths:
+  /articles:
+    get:
+      operationId: listArticles
+      responses:
+        "200":
+          description: JSON:API document
+          content:
+            application/vnd.api+json:
+              example:
+                data:
+                  - type: articles
+                    id: "1"
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/vnd.api+json" {
+		t.Fatalf("expected application/vnd.api+json, got %q", ct)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &document); err != nil {
+		t.Fatalf("expected JSON body: %v", err)
+	}
+	if _, ok := document["data"]; !ok {
+		t.Errorf("expected example body, got %v", document)
+	}
+}
+
+func TestServerCollapses2xxResponses(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              example:
+                id: 1
+        "400":
+          description: Bad request
+          content:
+            application/json:
+              example:
+                error: invalid
+`
+	post := func(handler http.Handler, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		return rec
+	}
+
+	handler := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.Collapse2xx = true
+	}).buildHandler()
+
+	rec := post(handler, "/pets")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 201 to be collapsed to %d, got %d", http.StatusOK, rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"id":1}` {
+		t.Errorf("expected the 201 body to be kept, got %q", body)
+	}
+
+	if rec := post(handler, "/pets?__statusCode=400"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected non-2xx status to be unchanged, got %d", rec.Code)
+	}
+
+	if rec := post(newSpecTestServer(t, spec, nil).buildHandler(), "/pets"); rec.Code != http.StatusCreated {
+		t.Errorf("expected %d by default, got %d", http.StatusCreated, rec.Code)
+	}
+}
+
+func TestServerErrorFormat(t *testing.T) {
+	handler := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.ErrorFormat = `{"code": {{status}}, "detail": "{{message}}", "allowed": "{{methods}}"}`
+	}).buildHandler()
+
+	tests := []struct {
+		method string
+		target string
+		want   map[string]any
+	}{
+		{
+			method: http.MethodDelete,
+			target: "/pets",
+			want:   map[string]any{"code": float64(405), "detail": "Method DELETE not allowed", "allowed": "GET"},
+		},
+		{
+			method: http.MethodGet,
+			target: "/unknown",
+			want:   map[string]any{"code": float64(404), "detail": "Not found", "allowed": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if rec.Code != int(tt.want["code"].(float64)) {
+			t.Errorf("%s %s: expected status %v, got %d", tt.method, tt.target, tt.want["code"], rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s %s: expected JSON content type, got %q", tt.method, tt.target, ct)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s %s: expected JSON body, got %q", tt.method, tt.target, rec.Body.String())
+		}
+		for key, want := range tt.want {
+			if body[key] != want {
+				t.Errorf("%s %s: expected %s=%v, got %v", tt.method, tt.target, key, want, body[key])
+			}
+		}
+	}
+}
+
+func TestServerProblemJSONErrors(t *testing.T) {
+	handler := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.ErrorFormat = config.ErrorFormatProblemJSON
+	}).buildHandler()
+
+	tests := []struct {
+		method  string
+		target  string
+		status  int
+		title   string
+		detail  string
+		methods []any
+	}{
+		{
+			method:  http.MethodDelete,
+			target:  "/pets",
+			status:  http.StatusMethodNotAllowed,
+			title:   "Method Not Allowed",
+			detail:  "Method DELETE not allowed",
+			methods: []any{"GET"},
+		},
+		{
+			method: http.MethodGet,
+			target: "/unknown",
+			status: http.StatusNotFound,
+			title:  "Not Found",
+			detail: "Not found",
+		},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.target, tt.status, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("%s %s: expected problem+json content type, got %q", tt.method, tt.target, ct)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s %s: expected JSON body, got %q", tt.method, tt.target, rec.Body.String())
+		}
+		want := map[string]any{
+			"type":     "about:blank",
+			"title":    tt.title,
+			"status":   float64(tt.status),
+			"detail":   tt.detail,
+			"instance": tt.target,
+		}
+		for key, value := range want {
+			if body[key] != value {
+				t.Errorf("%s %s: expected %s=%v, got %v", tt.method, tt.target, key, value, body[key])
+			}
+		}
+		if methods, _ := body["methods"].([]any); !reflect.DeepEqual(methods, tt.methods) {
+			t.Errorf("%s %s: expected methods %v, got %v", tt.method, tt.target, tt.methods, body["methods"])
+		}
+	}
+}
+
+func TestServerServesRawSpec(t *testing.T) {
+	// Comments and formatting must survive, so the spec is not re-encoded
+	spec := "# Pets API, maintained by the platform team\n" + adminTestSpec
+	srv := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.RawSpecPath = "/openapi.yaml"
+	})
+	srv.dynamicHandler = NewDynamicHandler(srv.buildHandler())
+	handler := srv.dynamicHandler
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		return rec
+	}
+
+	rec := get()
+	onDisk, err := os.ReadFile(srv.config.SpecFile)
+	if err != nil {
+		t.Fatalf("failed to read spec file: %v", err)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), onDisk) {
+		t.Errorf("expected served spec to match the file on disk, got %q", rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("expected application/yaml, got %q", ct)
+	}
+
+	updated := "# Updated\n" + adminTestSpec
+	if err := os.WriteFile(srv.config.SpecFile, []byte(updated), 0o644); err != nil {
+		t.Fatalf("failed to update spec file: %v", err)
+	}
+	if err := srv.Reload(context.Background()); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if body := get().Body.String(); body != updated {
+		t.Errorf("expected reloaded spec to be served, got %q", body)
+	}
+}
+
+func TestServerReportsBuildVersion(t *testing.T) {
+	defer func(v, c, d string) { version.Version, version.Commit, version.Date = v, c, d }(version.Version, version.Commit, version.Date)
+	version.Version, version.Commit, version.Date = "v1.2.3", "abc1234", "2025-01-02T03:04:05Z"
+
+	handler := newSpecTestServer(t, adminTestSpec, nil).buildHandler()
+	for _, path := range []string{"/health", "/docs"} {
+		var body struct {
+			Version   string `json:"version"`
+			Commit    string `json:"commit"`
+			BuildDate string `json:"build_date"`
+		}
+		rec := serve(handler, http.MethodGet, path, "")
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON from %s: %v", path, err)
+		}
+		if body.Version != "v1.2.3" || body.Commit != "abc1234" || body.BuildDate != "2025-01-02T03:04:05Z" {
+			t.Errorf("expected build information from %s, got %+v", path, body)
+		}
+	}
+}
+
+func TestServerServesDocumentation(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, nil)
+	srv.dynamicHandler = NewDynamicHandler(srv.buildHandler())
+	handler := srv.dynamicHandler
+
+	req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected HTML for browsers, got %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `url: "/openapi.json"`) {
+		t.Errorf("expected the page to load /openapi.json, got %s", rec.Body.String())
+	}
+
+	if rec := serve(handler, http.MethodGet, "/docs", ""); !strings.Contains(rec.Body.String(), `"endpoints"`) {
+		t.Errorf("expected a JSON summary without Accept: text/html, got %s", rec.Body.String())
+	}
+
+	title := func() string {
+		rec := serve(handler, http.MethodGet, "/openapi.json", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 from /openapi.json, got %d", rec.Code)
+		}
+		var doc struct {
+			Info struct {
+				Title string `json:"title"`
+			} `json:"info"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return doc.Info.Title
+	}
+	if got := title(); got != "Admin Test API" {
+		t.Errorf("expected the loaded spec, got title %q", got)
+	}
+
+	updated := strings.Replace(adminTestSpec, "Admin Test API", "Updated API", 1)
+	if err := os.WriteFile(srv.config.SpecFile, []byte(updated), 0o644); err != nil {
+		t.Fatalf("failed to update spec file: %v", err)
+	}
+	if err := srv.Reload(context.Background()); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if got := title(); got != "Updated API" {
+		t.Errorf("expected the reloaded spec, got title %q", got)
+	}
+}
+
+func TestServerServesParsedSpecAsYAML(t *testing.T) {
+	handler := newSpecTestServer(t, adminTestSpec, nil).buildHandler()
+
+	rec := serve(handler, http.MethodGet, "/openapi.yaml", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /openapi.yaml, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("expected application/yaml, got %q", ct)
+	}
+	var doc struct {
+		Info struct {
+			Title string `yaml:"title"`
+		} `yaml:"info"`
+		Paths map[string]any `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+	if doc.Info.Title != "Admin Test API" || len(doc.Paths) != 2 {
+		t.Errorf("expected the loaded spec, got %+v", doc)
+	}
+
+	// A raw_spec_path of /openapi.yaml serves the file as written instead
+	spec := "# Pets API\n" + adminTestSpec
+	handler = newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.RawSpecPath = "/openapi.yaml"
+	}).buildHandler()
+	if body := serve(handler, http.MethodGet, "/openapi.yaml", "").Body.String(); body != spec {
+		t.Errorf("expected the raw spec, got %q", body)
+	}
+}
+
+func TestServerHonorsMethodOverride(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    delete:
+      operationId: deletePet
+      responses:
+        "200":
+          description: Deleted
+          content:
+            application/json:
+              example:
+                deleted: true
+`
+	post := func(handler http.Handler) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pets/1", nil)
+		req.Header.Set("X-HTTP-Method-Override", "DELETE")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	handler := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.MethodOverride = true
+	}).buildHandler()
+	rec := post(handler)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the DELETE operation to be served, got %d", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"deleted":true}` {
+		t.Errorf("expected the DELETE example, got %q", body)
+	}
+
+	if rec := post(newSpecTestServer(t, spec, nil).buildHandler()); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected the override to be ignored by default, got %d", rec.Code)
+	}
+}
+
+func TestServerResponseCacheCanBeBypassed(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Tokens API
+  version: 1.0.0
+paths:
+  /tokens:
+    get:
+      operationId: getToken
+      responses:
+        "200":
+          description: A random token
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  token:
+                    type: string
+                    format: uuid
+`
+	get := func(handler http.Handler, target string) string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	cached := newSpecTestServer(t, spec, nil).buildHandler()
+	if get(cached, "/tokens") != get(cached, "/tokens") {
+		t.Error("expected identical cached responses by default")
+	}
+	if get(cached, "/tokens?__noCache=true") == get(cached, "/tokens?__noCache=true") {
+		t.Error("expected __noCache=true to regenerate the response")
+	}
+
+	uncached := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		disabled := false
+		cfg.Cache.Enabled = &disabled
+	}).buildHandler()
+	if get(uncached, "/tokens") == get(uncached, "/tokens") {
+		t.Error("expected cache.enabled: false to regenerate every response")
+	}
+}
+
+func TestServerOperationCacheExtension(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Tokens API
+  version: 1.0.0
+paths:
+  /fresh:
+    get:
+      operationId: getFresh
+      x-mock-cache: false
+      responses:
+        "200":
+          description: A token that is never cached
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Token"
+  /cached:
+    get:
+      operationId: getCached
+      x-mock-cache: true
+      responses:
+        "200":
+          description: A token that is always cached
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Token"
+  /default:
+    get:
+      operationId: getDefault
+      responses:
+        "200":
+          description: A token cached per cache.enabled
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Token"
+components:
+  schemas:
+    Token:
+      type: object
+      properties:
+        token:
+          type: string
+          format: uuid
+`
+	get := func(handler http.Handler, target string) string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+	if get(handler, "/fresh") == get(handler, "/fresh") {
+		t.Error("expected x-mock-cache: false to regenerate the response")
+	}
+	if get(handler, "/default") != get(handler, "/default") {
+		t.Error("expected operations without x-mock-cache to be cached")
+	}
+
+	uncached := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		disabled := false
+		cfg.Cache.Enabled = &disabled
+	}).buildHandler()
+	if get(uncached, "/cached") != get(uncached, "/cached") {
+		t.Error("expected x-mock-cache: true to cache despite cache.enabled: false")
+	}
+	if get(uncached, "/default") == get(uncached, "/default") {
+		t.Error("expected operations without x-mock-cache to follow cache.enabled")
+	}
+}
+
+func TestServerOperationDelayExtension(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Reports API
+  version: 1.0.0
+paths:
+  /reports:
+    post:
+      operationId: generateReport
+      x-mock-delay: 150ms
+      responses:
+        "200":
+          description: A slowly generated report
+          content:
+            application/json:
+              example: {"status": "done"}
+  /status:
+    get:
+      operationId: getStatus
+      responses:
+        "200":
+          description: A fast status check
+          content:
+            application/json:
+              example: {"status": "ok"}
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+	timed := func(method, target string) time.Duration {
+		start := time.Now()
+		if rec := serve(handler, method, target, ""); rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d for %s %s, got %d", http.StatusOK, method, target, rec.Code)
+		}
+		return time.Since(start)
+	}
+
+	if elapsed := timed(http.MethodPost, "/reports"); elapsed < 150*time.Millisecond {
+		t.Errorf("expected x-mock-delay to delay the response by 150ms, took %v", elapsed)
+	}
+	if elapsed := timed(http.MethodGet, "/status"); elapsed >= 150*time.Millisecond {
+		t.Errorf("expected operations without x-mock-delay not to be delayed, took %v", elapsed)
+	}
+	// __delay replaces the operation's delay
+	if elapsed := timed(http.MethodPost, "/reports?__delay=0"); elapsed >= 150*time.Millisecond {
+		t.Errorf("expected __delay to override x-mock-delay, took %v", elapsed)
+	}
+}
+
+func TestServerUnimplementedOperations(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Draft API
+  version: 1.0.0
+paths:
+  /reports:
+    get:
+      operationId: listReports
+      x-mock-unimplemented: true
+      responses:
+        "200":
+          description: Reports
+          content:
+            application/json:
+              example: [{"id": 1}]
+    post:
+      operationId: createReport
+      x-mock-unimplemented: false
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              example: {"id": 2}
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: Users
+          content:
+            application/json:
+              example: [{"name": "Ada"}]
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	rec := serve(handler, http.MethodGet, "/reports?__statusCode=200", "")
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected status %d for an unimplemented operation, got %d", http.StatusNotImplemented, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "not implemented") {
+		t.Errorf("expected the error to explain the operation is not implemented, got %s", rec.Body.String())
+	}
+
+	if rec := serve(handler, http.MethodPost, "/reports", ""); rec.Code != http.StatusCreated {
+		t.Errorf("expected status %d when x-mock-unimplemented is false, got %d", http.StatusCreated, rec.Code)
+	}
+	rec = serve(handler, http.MethodGet, "/users", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d for a normal operation, got %d", http.StatusOK, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Ada") {
+		t.Errorf("expected the normal operation's example, got %s", rec.Body.String())
+	}
+}
+
+func TestServerGenerationTimeout(t *testing.T) {
+	// A thousand generated objects take far longer than a microsecond
+	spec := `openapi: 3.0.0
+info:
+  title: Bulk API
+  version: 1.0.0
+paths:
+  /records:
+    get:
+      operationId: listRecords
+      responses:
+        "200":
+          description: A large generated list
+          content:
+            application/json:
+              schema:
+                type: array
+                minItems: 1000
+                items:
+                  type: object
+                  properties:
+                    id: {type: string, format: uuid}
+                    name: {type: string}
+                    email: {type: string, format: email}
+                    createdAt: {type: string, format: date-time}
+`
+	slow := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.GenerationTimeout = time.Microsecond
+	}).buildHandler()
+	for i := 0; i < 2; i++ {
+		rec := serve(slow, http.MethodGet, "/records", "")
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("request %d: expected status %d when generation exceeds the timeout, got %d", i+1, http.StatusServiceUnavailable, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "exceeding") {
+			t.Errorf("request %d: expected the error to report the exceeded budget, got %s", i+1, rec.Body.String())
+		}
+	}
+
+	generous := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.GenerationTimeout = time.Minute
+	}).buildHandler()
+	if rec := serve(generous, http.MethodGet, "/records", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected status %d within the timeout, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestServerResponseOverrideHeader(t *testing.T) {
+	override := func(handler http.Handler, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-Mock-Response", body)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	enabled := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.AllowResponseOverride = true
+	}).buildHandler()
+
+	rec := override(enabled, "/pets", `{"custom":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if rec.Body.String() != `{"custom":true}` {
+		t.Errorf("expected the overridden body, got %s", rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected a JSON override to be served as JSON, got %s", ct)
+	}
+
+	rec = override(enabled, "/pets?__statusCode=418", "not json")
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("expected __statusCode to set the override status, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("expected a non-JSON override to be served as text, got %s", ct)
+	}
+
+	disabled := newSpecTestServer(t, adminTestSpec, nil).buildHandler()
+	rec = override(disabled, "/pets", `{"custom":true}`)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "custom") {
+		t.Errorf("expected the header to be ignored when the override is disabled, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServerUsesServerBasePaths(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Versioned API
+  version: 1.0.0
+servers:
+  - url: /api/v1
+  - url: https://internal.example.com/internal
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              example: {"name": "Fido"}
+`
+	handler := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.UseServerBasePath = true
+	}).buildHandler()
+
+	for _, target := range []string{"/api/v1/pets/1", "/internal/pets/1"} {
+		if rec := serve(handler, http.MethodGet, target, ""); rec.Code != http.StatusOK {
+			t.Errorf("expected %s to be served, got %d", target, rec.Code)
+		}
+	}
+	if rec := serve(handler, http.MethodGet, "/pets/1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected the unprefixed path to be unrouted, got %d", rec.Code)
+	}
+
+	unprefixed := newSpecTestServer(t, spec, nil).buildHandler()
+	if rec := serve(unprefixed, http.MethodGet, "/pets/1", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected spec paths to be served as-is by default, got %d", rec.Code)
+	}
+}
+
+func TestServerPathPrefixRewriting(t *testing.T) {
+	specFor := func(path string) string {
+		return `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  ` + path + `:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              example: [{"name": "Fido"}]
+`
+	}
+
+	tests := []struct {
+		name      string
+		specPath  string
+		configure func(cfg *config.Config)
+		want      map[string]int
+	}{
+		{
+			name:     "strip",
+			specPath: "/pets",
+			configure: func(cfg *config.Config) {
+				cfg.Server.StripPathPrefix = "/mock/"
+			},
+			want: map[string]int{
+				"/mock/pets":    http.StatusOK,
+				"/pets":         http.StatusOK,
+				"/mock/health":  http.StatusOK,
+				"/mockery/pets": http.StatusNotFound,
+			},
+		},
+		{
+			name:     "add",
+			specPath: "/api/pets",
+			configure: func(cfg *config.Config) {
+				cfg.Server.AddPathPrefix = "/api"
+			},
+			want: map[string]int{
+				"/pets":     http.StatusOK,
+				"/api/pets": http.StatusOK,
+				"/health":   http.StatusOK,
+				"/orders":   http.StatusNotFound,
+			},
+		},
+		{
+			name:     "strip then add",
+			specPath: "/api/pets",
+			configure: func(cfg *config.Config) {
+				cfg.Server.StripPathPrefix = "/mock"
+				cfg.Server.AddPathPrefix = "/api"
+			},
+			want: map[string]int{
+				"/mock/pets":     http.StatusOK,
+				"/mock/api/pets": http.StatusOK,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newSpecTestServer(t, specFor(tt.specPath), tt.configure).buildHandler()
+			for target, want := range tt.want {
+				if rec := serve(handler, http.MethodGet, target, ""); rec.Code != want {
+					t.Errorf("expected status %d for %s, got %d", want, target, rec.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestServerLenientPathMatching(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              example: [{"name": "Fido"}]
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  petId:
+                    type: string
+`
+	strict := newSpecTestServer(t, spec, nil).buildHandler()
+	for _, target := range []string{"/pets/", "/Pets"} {
+		if rec := serve(strict, http.MethodGet, target, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("expected %s to be unrouted by default, got %d", target, rec.Code)
+		}
+	}
+
+	lenient := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		strictSlash := false
+		cfg.Server.StrictSlash = &strictSlash
+		cfg.Server.CaseInsensitivePaths = true
+	}).buildHandler()
+	for _, target := range []string{"/pets", "/pets/", "/Pets", "/PETS/"} {
+		if rec := serve(lenient, http.MethodGet, target, ""); rec.Code != http.StatusOK {
+			t.Errorf("expected %s to be served from /pets, got %d", target, rec.Code)
+		}
+	}
+
+	rec := serve(lenient, http.MethodGet, "/PETS/AbC/", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected /PETS/AbC/ to be served from /pets/{petId}, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"petId":"AbC"`) {
+		t.Errorf("expected the path parameter to keep its case, got %s", rec.Body.String())
+	}
+
+	if rec := serve(lenient, http.MethodDelete, "/Pets", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected unsupported methods on a matched path to get 405, got %d", rec.Code)
+	}
+}
+
+func TestServerRouteMaxRequestSize(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Uploads API
+  version: 1.0.0
+paths:
+  /uploads:
+    post:
+      operationId: upload
+      responses:
+        "201":
+          description: Uploaded
+          content:
+            application/json:
+              example: {"stored": true}
+  /notes:
+    post:
+      operationId: createNote
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              example: {"stored": true}
+  /comments:
+    post:
+      operationId: createComment
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              example: {"stored": true}
+`
+	handler := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Server.RouteMaxRequestSize = map[string]int64{
+			"upload":         20 << 20,
+			"post /comments": 16,
+		}
+	}).buildHandler()
+
+	large := strings.Repeat("a", 11<<20)
+	if rec := serve(handler, http.MethodPost, "/uploads", large); rec.Code != http.StatusCreated {
+		t.Errorf("expected the upload route's higher limit to accept 11MB, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodPost, "/notes", large); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected other routes to keep the default limit, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodPost, "/comments", `{"text": "too long for this route"}`); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected a route limit by method and path to reject the body, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodPost, "/comments", `{"text": "ok"}`); rec.Code != http.StatusCreated {
+		t.Errorf("expected a body within the route limit to pass, got %d", rec.Code)
+	}
+}
+
+func TestServerAutoHead(t *testing.T) {
+	head := func(handler http.Handler) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/pets", nil))
+		return rec
+	}
+
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.AutoHead = true
+	})
+	handler := srv.buildHandler()
+
+	getRec := httptest.NewRecorder()
+	handler.ServeHTTP(getRec, httptest.NewRequest(http.MethodGet, "/pets", nil))
+
+	rec := head(handler)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected HEAD on a GET-only path to succeed, got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected no body, got %q", rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != getRec.Header().Get("Content-Type") {
+		t.Errorf("expected the GET Content-Type %q, got %q", getRec.Header().Get("Content-Type"), ct)
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != fmt.Sprint(getRec.Body.Len()) {
+		t.Errorf("expected Content-Length %d, got %q", getRec.Body.Len(), cl)
+	}
+
+	if rec := head(newSpecTestServer(t, adminTestSpec, nil).buildHandler()); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for HEAD by default, got %d", rec.Code)
+	}
+}
+
+func TestServerSendsDeclaredTrailers(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Trailers
+  version: 1.0.0
+paths:
+  /export:
+    get:
+      operationId: export
+      responses:
+        "200":
+          description: ok
+          x-mock-trailers:
+            X-Checksum: $sha256
+            X-Export-Status: complete
+          content:
+            application/json:
+              example:
+                rows: 3
+`
+	ts := httptest.NewServer(newSpecTestServer(t, spec, nil).buildHandler())
+	defer ts.Close()
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(ts.URL + "/export")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+
+		if got, want := resp.Trailer.Get("X-Checksum"), fmt.Sprintf("%x", sha256.Sum256(body)); got != want {
+			t.Errorf("request %d: expected X-Checksum trailer %q, got %q", i, want, got)
+		}
+		if got := resp.Trailer.Get("X-Export-Status"); got != "complete" {
+			t.Errorf("request %d: expected X-Export-Status trailer, got %q", i, got)
+		}
+	}
+}
+
+func TestServerFillsPathParameters(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Path Params
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                  name:
+                    type: string
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	for _, id := range []int{42, 7} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/pets/%d", id), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body["id"] != float64(id) {
+			t.Errorf("expected id %d from the path, got %v", id, body["id"])
+		}
+	}
+}
+
+func TestServerEchoesRequests(t *testing.T) {
+	handler := newSpecTestServer(t, adminTestSpec, nil).buildHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/pets?q=fido&__echo=true", strings.NewReader(`{"name":"Fido"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Client", "tests")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var echo struct {
+		Method  string              `json:"method"`
+		Path    string              `json:"path"`
+		Query   map[string][]string `json:"query"`
+		Headers map[string][]string `json:"headers"`
+		Body    string              `json:"body"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &echo); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if echo.Method != http.MethodGet || echo.Path != "/pets" {
+		t.Errorf("expected GET /pets, got %s %s", echo.Method, echo.Path)
+	}
+	if got := echo.Query["q"]; len(got) != 1 || got[0] != "fido" {
+		t.Errorf("expected query q=fido, got %v", echo.Query)
+	}
+	if _, ok := echo.Query["__echo"]; ok {
+		t.Error("expected __echo to be left out of the query")
+	}
+	if got := echo.Headers["X-Client"]; len(got) != 1 || got[0] != "tests" {
+		t.Errorf("expected X-Client header, got %v", echo.Headers)
+	}
+	if got := echo.Headers["Authorization"]; len(got) != 1 || got[0] != "[REDACTED]" {
+		t.Errorf("expected Authorization to be redacted, got %v", got)
+	}
+	if echo.Body != `{"name":"Fido"}` {
+		t.Errorf("expected the request body, got %q", echo.Body)
+	}
+
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Echo.Enabled = true
+	})
+	if rec := serve(srv.buildHandler(), http.MethodGet, "/pets?__echo=false", ""); strings.Contains(rec.Body.String(), `"method"`) {
+		t.Errorf("expected __echo=false to return the mock, got %s", rec.Body.String())
+	}
+	if rec := serve(srv.buildHandler(), http.MethodGet, "/pets", ""); !strings.Contains(rec.Body.String(), `"method":"GET"`) {
+		t.Errorf("expected echo.enabled to echo every request, got %s", rec.Body.String())
+	}
+}
+
+func TestServerReadsSpecFromStdin(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = stdin }()
+
+	go func() {
+		_, _ = writer.WriteString(adminTestSpec)
+		_ = writer.Close()
+	}()
+
+	cfg := config.DefaultConfig()
+	cfg.SpecFile = "-"
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server from stdin: %v", err)
+	}
+	handler := srv.buildHandler()
+
+	if rec := serve(handler, http.MethodGet, "/pets", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Fido") {
+		t.Errorf("expected the stdin spec's /pets route, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(handler, http.MethodGet, "/health", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected a spec from stdin to be healthy, got %d", rec.Code)
+	}
+}
+
+func TestServerCachesExamplesForUnmatchedPathParameters(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Path Params
+  version: 1.0.0
+paths:
+  /pets/{petId}/tags:
+    get:
+      operationId: getPetTags
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  tag:
+                    type: string
+                    format: uuid
+`
+	handler := newSpecTestServer(t, spec, nil).buildHandler()
+
+	// No property is named after petId, so every path shares the parser's
+	// cached example, as cache.warmup expects
+	var bodies []string
+	for _, id := range []int{42, 7} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/pets/%d/tags", id), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		bodies = append(bodies, rec.Body.String())
+	}
+	if bodies[0] != bodies[1] {
+		t.Errorf("expected the cached example for both paths, got %s and %s", bodies[0], bodies[1])
+	}
+}
+
 func TestServerReloadRebuildsRoutes(t *testing.T) {
 	dir := t.TempDir()
 	specFile := filepath.Join(dir, "reload.yaml")
@@ -172,3 +1563,302 @@ paths:
 		t.Fatalf("expected status %d for removed route, got %d", http.StatusNotFound, rec.Code)
 	}
 }
+
+func TestProxyOnlyForwardsPathPrefixes(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"source":"backend"}`))
+	}))
+	defer backend.Close()
+
+	spec := `openapi: 3.0.0
+info:
+  title: Hybrid API
+  version: 1.0.0
+paths:
+  /api/pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              example:
+                source: mock
+`
+	handler := newSpecTestServer(t, spec, func(cfg *config.Config) {
+		cfg.Proxy.Enabled = true
+		cfg.Proxy.Target = backend.URL
+		cfg.Proxy.PathPrefixes = []string{"/api/legacy"}
+	}).buildHandler()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantSource string
+	}{
+		{"/api/pets", http.StatusOK, "mock"},
+		{"/api/legacy/users", http.StatusOK, "backend"},
+		{"/api/other", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", tt.path, tt.wantStatus, rec.Code)
+		}
+		if tt.wantSource == "" {
+			continue
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: failed to decode body: %v", tt.path, err)
+		}
+		if body["source"] != tt.wantSource {
+			t.Errorf("%s: expected source %q, got %v", tt.path, tt.wantSource, body["source"])
+		}
+	}
+}
+
+func TestProxyRecordsForwardedResponses(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"source":"backend"}`))
+	}))
+	defer backend.Close()
+
+	recordDir := t.TempDir()
+	handler := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Proxy.Enabled = true
+		cfg.Proxy.Target = backend.URL
+		cfg.Proxy.RecordDir = recordDir
+	}).buildHandler()
+
+	for _, target := range []string{"/pets", "/legacy/users"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", target, rec.Code)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(recordDir, middleware.RecordingsFile))
+	if err != nil {
+		t.Fatalf("failed to read recordings: %v", err)
+	}
+
+	// Only the proxied request is recorded, not the mocked one
+	var recording middleware.Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		t.Fatalf("expected a single recording line, got %q: %v", data, err)
+	}
+	if recording.Path != "/legacy/users" || recording.Status != http.StatusOK || string(recording.Body) != `{"source":"backend"}` {
+		t.Errorf("unexpected recording: %+v", recording)
+	}
+}
+
+func TestProxyReplaysRecordedResponses(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"source":"backend"}`))
+	}))
+	defer backend.Close()
+
+	recordDir := t.TempDir()
+	recording := `{"method":"GET","path":"/legacy/users","status":200,"content_type":"application/json","body":"eyJzb3VyY2UiOiJyZWNvcmRpbmcifQ=="}` + "\n"
+	if err := os.WriteFile(filepath.Join(recordDir, middleware.RecordingsFile), []byte(recording), 0o600); err != nil {
+		t.Fatalf("failed to write recordings: %v", err)
+	}
+
+	newHandler := func(fallback bool) http.Handler {
+		return newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+			cfg.Proxy.Enabled = true
+			cfg.Proxy.Target = backend.URL
+			cfg.Proxy.Mode = config.ProxyModeReplay
+			cfg.Proxy.RecordDir = recordDir
+			cfg.Proxy.ReplayFallback = fallback
+		}).buildHandler()
+	}
+
+	tests := []struct {
+		fallback   bool
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{false, "/legacy/users", http.StatusOK, `{"source":"recording"}`},
+		{false, "/legacy/orders", http.StatusNotFound, ""},
+		{true, "/legacy/orders", http.StatusOK, `{"source":"backend"}`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		newHandler(tt.fallback).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s (fallback %v): expected status %d, got %d", tt.path, tt.fallback, tt.wantStatus, rec.Code)
+		}
+		if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+			t.Errorf("%s (fallback %v): expected body %s, got %s", tt.path, tt.fallback, tt.wantBody, rec.Body.String())
+		}
+	}
+}
+
+func TestServerReadinessDegradesAfterMaxStale(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.HotReload.MaxStale = 50 * time.Millisecond
+	})
+	srv.dynamicHandler = NewDynamicHandler(srv.buildHandler())
+	status := func(path string) int {
+		return serve(srv.dynamicHandler, http.MethodGet, path, "").Code
+	}
+
+	if err := os.WriteFile(srv.config.SpecFile, []byte("openapi: [broken"), 0o644); err != nil {
+		t.Fatalf("failed to break spec: %v", err)
+	}
+	if err := srv.Reload(context.Background()); err == nil {
+		t.Fatal("expected reload of a broken spec to fail")
+	}
+	if got := status("/ready"); got != http.StatusOK {
+		t.Errorf("expected readiness within max_stale, got %d", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := srv.Reload(context.Background()); err == nil {
+		t.Fatal("expected reload of a broken spec to fail")
+	}
+	if got := status("/ready"); got != http.StatusServiceUnavailable {
+		t.Errorf("expected readiness to degrade after max_stale, got %d", got)
+	}
+	if got := status("/health"); got != http.StatusOK {
+		t.Errorf("expected liveness to stay healthy, got %d", got)
+	}
+	if got := status("/pets"); got != http.StatusOK {
+		t.Errorf("expected the last good spec to keep serving, got %d", got)
+	}
+
+	if err := os.WriteFile(srv.config.SpecFile, []byte(adminTestSpec), 0o644); err != nil {
+		t.Fatalf("failed to restore spec: %v", err)
+	}
+	if err := srv.Reload(context.Background()); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if got := status("/ready"); got != http.StatusOK {
+		t.Errorf("expected readiness after a successful reload, got %d", got)
+	}
+}
+
+func TestServerReadinessProbesProxyTarget(t *testing.T) {
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer reachable.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := unreachable.URL
+	unreachable.Close()
+
+	tests := []struct {
+		name        string
+		target      string
+		healthCheck bool
+		want        int
+	}{
+		{"reachable target", reachable.URL, true, http.StatusOK},
+		{"unreachable target", unreachableURL, true, http.StatusServiceUnavailable},
+		{"target returning 5xx", failing.URL, true, http.StatusServiceUnavailable},
+		{"health check disabled", unreachableURL, false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+				cfg.Proxy.Enabled = true
+				cfg.Proxy.Target = tt.target
+				cfg.Proxy.HealthCheck = tt.healthCheck
+			}).buildHandler()
+
+			if rec := serve(handler, http.MethodGet, "/ready", ""); rec.Code != tt.want {
+				t.Errorf("expected /ready status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServerFormatsJSONResponses(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Format API
+  version: 1.0.0
+paths:
+  /user:
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          description: A user
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  id:
+                    type: integer
+              example:
+                id: 1
+                name: Ada
+`
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config)
+		expected  string
+	}{
+		{
+			name:     "compact and alphabetical by default",
+			expected: `{"id":1,"name":"Ada"}`,
+		},
+		{
+			name: "pretty",
+			configure: func(cfg *config.Config) {
+				cfg.Response.Pretty = true
+			},
+			expected: "{\n  \"id\": 1,\n  \"name\": \"Ada\"\n}",
+		},
+		{
+			name: "pretty without indentation",
+			configure: func(cfg *config.Config) {
+				indent := 0
+				cfg.Response.Pretty = true
+				cfg.Response.Indent = &indent
+			},
+			expected: "{\n\"id\": 1,\n\"name\": \"Ada\"\n}",
+		},
+		{
+			name: "pretty in declared order",
+			configure: func(cfg *config.Config) {
+				cfg.Response.Pretty = true
+				indent := 4
+				cfg.Response.Indent = &indent
+				cfg.Response.PreserveOrder = true
+			},
+			expected: "{\n    \"name\": \"Ada\",\n    \"id\": 1\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newSpecTestServer(t, spec, tt.configure).buildHandler()
+			for i := 0; i < 3; i++ {
+				rec := serve(handler, http.MethodGet, "/user?__noCache=true", "")
+				if got := strings.TrimSpace(rec.Body.String()); got != tt.expected {
+					t.Fatalf("expected %q, got %q", tt.expected, got)
+				}
+			}
+		})
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/leslieo2/go-spec-mock/internal/config"
 	"github.com/leslieo2/go-spec-mock/internal/observability"
 	"github.com/leslieo2/go-spec-mock/internal/parser"
+	"github.com/leslieo2/go-spec-mock/internal/version"
 )
 
 func TestServer_ObservabilityEndpoints(t *testing.T) {
@@ -107,8 +110,8 @@ func TestHealthHandler(t *testing.T) {
 		t.Errorf("Expected status 'healthy', got '%s'", health.Status)
 	}
 
-	if health.Version != "1.0.0" {
-		t.Errorf("Expected version '1.0.0', got '%s'", health.Version)
+	if health.Version != version.Version {
+		t.Errorf("Expected version '%s', got '%s'", version.Version, health.Version)
 	}
 
 	if health.Uptime == "" {
@@ -126,6 +129,56 @@ func TestHealthHandler(t *testing.T) {
 	if !health.Checks["routes"] {
 		t.Error("Expected routes check to be true")
 	}
+	if !health.Checks["spec_file"] {
+		t.Error("Expected spec_file check to be true")
+	}
+}
+
+func TestHealthHandler_SpecFileMissing(t *testing.T) {
+	specData, err := os.ReadFile("../../examples/petstore.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read petstore spec: %v", err)
+	}
+	specFile := filepath.Join(t.TempDir(), "petstore.yaml")
+	if err := os.WriteFile(specFile, specData, 0o644); err != nil {
+		t.Fatalf("Failed to write spec file: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.SpecFile = specFile
+	server, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	defer func() { _ = server.logger.Sync() }()
+
+	if err := os.Remove(specFile); err != nil {
+		t.Fatalf("Failed to remove spec file: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+
+	server.healthHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+
+	var health observability.HealthStatus
+	if err := json.NewDecoder(w.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode health response: %v", err)
+	}
+
+	if health.Status != "unhealthy" {
+		t.Errorf("Expected status 'unhealthy', got '%s'", health.Status)
+	}
+	if health.Checks["spec_file"] {
+		t.Error("Expected spec_file check to be false")
+	}
+	if !health.Checks["parser"] {
+		t.Error("Expected parser check to remain true for the in-memory spec")
+	}
 }
 
 func TestReadinessHandler_Ready(t *testing.T) {
@@ -192,6 +245,36 @@ func TestReadinessHandler_NotReady(t *testing.T) {
 	}
 }
 
+func TestWarmupDelayGatesReadinessAndRoutes(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.WarmupDelay = 300 * time.Millisecond
+	})
+	handler := srv.buildHandler()
+
+	if rec := serve(handler, http.MethodGet, "/ready", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected readiness 503 during warmup, got %d", rec.Code)
+	}
+	rec := serve(handler, http.MethodGet, "/pets", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected route 503 during warmup, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected Retry-After 1 during warmup, got %q", rec.Header().Get("Retry-After"))
+	}
+	if rec := serve(handler, http.MethodGet, "/health", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected health 200 during warmup, got %d", rec.Code)
+	}
+
+	time.Sleep(350 * time.Millisecond)
+
+	if rec := serve(handler, http.MethodGet, "/ready", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected readiness 200 after warmup, got %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, "/pets", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected route 200 after warmup, got %d", rec.Code)
+	}
+}
+
 func TestDocumentationHandler(t *testing.T) {
 	cfg := &config.Config{
 		SpecFile: "../../examples/petstore.yaml",
@@ -238,8 +321,8 @@ func TestDocumentationHandler(t *testing.T) {
 		t.Errorf("Expected message 'Go-Spec-Mock Enterprise API Server', got '%s'", doc.Message)
 	}
 
-	if doc.Version != "1.0.0" {
-		t.Errorf("Expected version '1.0.0', got '%s'", doc.Version)
+	if doc.Version != version.Version {
+		t.Errorf("Expected version '%s', got '%s'", version.Version, doc.Version)
 	}
 
 	if len(doc.Endpoints) == 0 {
@@ -1,16 +1,23 @@
 package server
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
 	"github.com/leslieo2/go-spec-mock/internal/server/middleware"
 	"go.uber.org/zap"
 )
@@ -67,8 +74,8 @@ func TestDefaultCORSConfig(t *testing.T) {
 		t.Error("Expected default allowed methods")
 	}
 
-	if cfg.MaxAge != 86400 {
-		t.Errorf("Expected max age 86400, got %d", cfg.MaxAge)
+	if cfg.PreflightMaxAge() != 86400 {
+		t.Errorf("Expected max age 86400, got %d", cfg.PreflightMaxAge())
 	}
 }
 
@@ -80,6 +87,7 @@ func TestCORSMiddleware(t *testing.T) {
 		[]string{"Content-Type"},
 		true,
 		3600,
+		0,
 		zap.NewNop(),
 	)
 
@@ -116,6 +124,7 @@ func TestCORSMiddleware_Preflight(t *testing.T) {
 		[]string{"Content-Type"},
 		false,
 		0,
+		0,
 		zap.NewNop(),
 	)
 
@@ -131,8 +140,37 @@ func TestCORSMiddleware_Preflight(t *testing.T) {
 
 	handler.ServeHTTP(rec, req)
 
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 for preflight, got %d", rec.Code)
+	}
+}
+
+func TestCORSMiddleware_PreflightStatusAndMaxAge(t *testing.T) {
+	corsMiddleware := middleware.NewCORSMiddleware(
+		[]string{"*"},
+		[]string{"GET", "POST"},
+		[]string{"Content-Type"},
+		false,
+		600,
+		http.StatusOK,
+		zap.NewNop(),
+	)
+
+	handler := corsMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Preflight request should not reach the next handler")
+	}))
+
+	req := httptest.NewRequest("OPTIONS", "/test", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
 	if rec.Code != http.StatusOK {
-		t.Errorf("Expected status 200 for preflight, got %d", rec.Code)
+		t.Errorf("Expected configured status 200 for preflight, got %d", rec.Code)
+	}
+	if maxAge := rec.Header().Get("Access-Control-Max-Age"); maxAge != "600" {
+		t.Errorf("Expected Access-Control-Max-Age 600, got %q", maxAge)
 	}
 }
 
@@ -266,6 +304,38 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewWithoutOperations(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "empty.yaml")
+	spec := "openapi: 3.0.0\ninfo:\n  title: Empty API\n  version: 1.0.0\npaths: {}\n"
+	if err := os.WriteFile(specFile, []byte(spec), 0o644); err != nil {
+		t.Fatalf("Failed to write spec file: %v", err)
+	}
+	newServer := func(configure func(cfg *config.Config)) (*Server, error) {
+		cfg := config.DefaultConfig()
+		cfg.SpecFile = specFile
+		configure(cfg)
+		return New(cfg)
+	}
+
+	if _, err := newServer(func(cfg *config.Config) {}); err != nil {
+		t.Errorf("Expected an empty spec to only warn by default, got %v", err)
+	}
+
+	_, err := newServer(func(cfg *config.Config) { cfg.Strict = true })
+	if err == nil || !strings.Contains(err.Error(), "defines no operations") {
+		t.Errorf("Expected strict mode to reject an empty spec, got %v", err)
+	}
+
+	_, err = newServer(func(cfg *config.Config) {
+		cfg.Strict = true
+		cfg.Proxy.Enabled = true
+		cfg.Proxy.Target = "http://localhost:9999"
+	})
+	if err != nil {
+		t.Errorf("Expected an empty spec to be accepted when proxying, got %v", err)
+	}
+}
+
 func TestProxyConfiguration(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -555,6 +625,70 @@ func TestGenerateCacheKey_InternalParameters(t *testing.T) {
 	}
 }
 
+func TestCacheDebugHeaders(t *testing.T) {
+	get := func(handler http.Handler, target string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d", target, rec.Code)
+		}
+		return rec
+	}
+
+	handler := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.CacheDebug = true
+	}).buildHandler()
+
+	first := get(handler, "/pets?limit=5")
+	if got := first.Header().Get("X-Mock-Cache"); got != "MISS" {
+		t.Errorf("Expected X-Mock-Cache MISS on first request, got %q", got)
+	}
+	key := first.Header().Get("X-Mock-Cache-Key")
+	if key == "" || strings.Contains(key, "limit") {
+		t.Errorf("Expected a hashed X-Mock-Cache-Key, got %q", key)
+	}
+
+	second := get(handler, "/pets?limit=5")
+	if got := second.Header().Get("X-Mock-Cache"); got != "HIT" {
+		t.Errorf("Expected X-Mock-Cache HIT on identical request, got %q", got)
+	}
+	if got := second.Header().Get("X-Mock-Cache-Key"); got != key {
+		t.Errorf("Expected the same cache key on a hit, got %q and %q", key, got)
+	}
+
+	if got := get(handler, "/pets?limit=6").Header().Get("X-Mock-Cache-Key"); got == key {
+		t.Error("Expected a different cache key for different query parameters")
+	}
+
+	quiet := newSpecTestServer(t, adminTestSpec, nil).buildHandler()
+	if got := get(quiet, "/pets").Header().Get("X-Mock-Cache"); got != "" {
+		t.Errorf("Expected no cache headers by default, got %q", got)
+	}
+}
+
+func TestMethodNotAllowedAllowHeader(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, nil)
+
+	rec := httptest.NewRecorder()
+	srv.sendMethodNotAllowedResponse(rec, httptest.NewRequest(http.MethodDelete, "/pets", nil), []string{"GET", "POST"})
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("expected Allow header %q, got %q", "GET, POST", allow)
+	}
+
+	// Requests routed through the handler report the path's methods
+	rec = serve(srv.buildHandler(), http.MethodDelete, "/pets", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET" {
+		t.Errorf("expected Allow header %q, got %q", "GET", allow)
+	}
+}
+
 func TestServer_Start_TLS(t *testing.T) {
 	// Create a handler for the test server
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -635,3 +769,96 @@ func TestDynamicHandler(t *testing.T) {
 		t.Errorf("Expected body 'updated', got '%s'", rec2.Body.String())
 	}
 }
+
+func TestShutdownTimeoutBySignal(t *testing.T) {
+	if got := shutdownTimeout(syscall.SIGTERM, 0); got != constants.ServerShutdownTimeout {
+		t.Errorf("expected SIGTERM to drain for %v, got %v", constants.ServerShutdownTimeout, got)
+	}
+	if got := shutdownTimeout(os.Interrupt, 0); got != constants.ServerInterruptShutdownTimeout {
+		t.Errorf("expected SIGINT to drain for %v, got %v", constants.ServerInterruptShutdownTimeout, got)
+	}
+	if constants.ServerInterruptShutdownTimeout >= constants.ServerShutdownTimeout {
+		t.Error("expected SIGINT to shut down faster than SIGTERM")
+	}
+
+	if got := shutdownTimeout(syscall.SIGTERM, 5*time.Minute); got != 5*time.Minute {
+		t.Errorf("expected SIGTERM to use the configured timeout, got %v", got)
+	}
+	if got := shutdownTimeout(os.Interrupt, 500*time.Millisecond); got != 500*time.Millisecond {
+		t.Errorf("expected SIGINT to use a configured timeout shorter than its own, got %v", got)
+	}
+}
+
+func TestNewHTTPServerUsesConfiguredTimeouts(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.ReadTimeout = 5 * time.Second
+		cfg.Server.WriteTimeout = 10 * time.Minute
+		cfg.Server.IdleTimeout = 0
+	})
+	server := srv.newHTTPServer(srv.buildHandler())
+
+	if server.ReadTimeout != 5*time.Second {
+		t.Errorf("expected read timeout 5s, got %v", server.ReadTimeout)
+	}
+	if server.WriteTimeout != 10*time.Minute {
+		t.Errorf("expected write timeout 10m, got %v", server.WriteTimeout)
+	}
+	if server.IdleTimeout != constants.ServerIdleTimeout {
+		t.Errorf("expected an unset idle timeout to default to %v, got %v", constants.ServerIdleTimeout, server.IdleTimeout)
+	}
+}
+
+func TestNewHTTPServerDisablesKeepAlives(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.DisableKeepAlives = true
+	})
+	ts := httptest.NewUnstartedServer(nil)
+	ts.Config = srv.newHTTPServer(srv.buildHandler())
+	ts.Start()
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/pets")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if !resp.Close {
+		t.Error("expected the server to close the connection after the response")
+	}
+}
+
+func TestShutdownCancelsRequestsAfterTimeout(t *testing.T) {
+	srv := newSpecTestServer(t, adminTestSpec, func(cfg *config.Config) {
+		cfg.Server.ShutdownTimeout = 100 * time.Millisecond
+	})
+	srv.server = srv.newHTTPServer(srv.buildHandler())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() { _ = srv.server.Serve(listener) }()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resp, err := http.Get("http://" + listener.Addr().String() + "/pets?__delay=20s")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	if err := srv.Shutdown(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the drain to time out, got %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the delayed request to be cancelled after the shutdown timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected shutdown within the configured timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"crypto/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/parser"
+)
+
+// sessionStore simulates cookie-based sessions: the login operation issues a
+// session cookie and protected operations require a live one
+type sessionStore struct {
+	cfg       config.SessionConfig
+	login     map[string]struct{}
+	logout    map[string]struct{}
+	protected map[string]struct{}
+
+	mu       sync.Mutex
+	sessions map[string]time.Time // token to expiry
+}
+
+// newSessionStore creates a session store, or returns nil when sessions are disabled
+func newSessionStore(cfg config.SessionConfig) *sessionStore {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &sessionStore{
+		cfg:       cfg,
+		login:     operationSet([]string{cfg.Login}),
+		logout:    operationSet([]string{cfg.Logout}),
+		protected: operationSet(cfg.Protected),
+		sessions:  make(map[string]time.Time),
+	}
+}
+
+// operationSet builds a set of operations keyed by operationKey, skipping empty entries
+func operationSet(operations []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(operations))
+	for _, operation := range operations {
+		if operation != "" {
+			set[operationKey(operation)] = struct{}{}
+		}
+	}
+	return set
+}
+
+// handle applies session behavior to a matched route. It sets or clears the
+// session cookie for login and logout, and returns false after rejecting a
+// protected request without a valid session.
+func (st *sessionStore) handle(w http.ResponseWriter, r *http.Request, route *parser.Route) bool {
+	switch {
+	case routeInOperations(route, st.login):
+		http.SetCookie(w, st.cookie(r, st.create(), int(st.cfg.TTL.Seconds())))
+	case routeInOperations(route, st.logout):
+		if cookie, err := r.Cookie(st.cfg.CookieName); err == nil {
+			st.delete(cookie.Value)
+		}
+		http.SetCookie(w, st.cookie(r, "", -1))
+	case routeInOperations(route, st.protected):
+		cookie, err := r.Cookie(st.cfg.CookieName)
+		return err == nil && st.valid(cookie.Value)
+	}
+	return true
+}
+
+// cookie builds the session cookie; a negative maxAge deletes it
+func (st *sessionStore) cookie(r *http.Request, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     st.cfg.CookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// create starts a new session and returns its token
+func (st *sessionStore) create() string {
+	token := rand.Text()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now()
+	for existing, expires := range st.sessions {
+		if !now.Before(expires) {
+			delete(st.sessions, existing)
+		}
+	}
+	st.sessions[token] = now.Add(st.cfg.TTL)
+	return token
+}
+
+// valid reports whether a token belongs to a live session
+func (st *sessionStore) valid(token string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	expires, ok := st.sessions[token]
+	return ok && time.Now().Before(expires)
+}
+
+// delete ends a session
+func (st *sessionStore) delete(token string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.sessions, token)
+}
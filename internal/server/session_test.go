@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+)
+
+const sessionTestSpec = `openapi: 3.0.0
+info:
+  title: Auth API
+  version: 1.0.0
+paths:
+  /login:
+    post:
+      operationId: login
+      responses:
+        "200":
+          description: Logged in
+          content:
+            application/json:
+              example:
+                ok: true
+  /logout:
+    post:
+      operationId: logout
+      responses:
+        "204":
+          description: Logged out
+  /profile:
+    get:
+      operationId: getProfile
+      responses:
+        "200":
+          description: Profile
+          content:
+            application/json:
+              example:
+                name: Ada
+`
+
+func TestSessionCookieFlow(t *testing.T) {
+	handler := newSpecTestServer(t, sessionTestSpec, func(cfg *config.Config) {
+		cfg.Sessions.Enabled = true
+		cfg.Sessions.Login = "POST /login"
+		cfg.Sessions.Logout = "logout"
+		cfg.Sessions.Protected = []string{"getProfile"}
+	}).buildHandler()
+
+	do := func(method, target string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/profile", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 without a session, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/profile", &http.Cookie{Name: "session_id", Value: "forged"}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 with an unknown session, got %d", rec.Code)
+	}
+
+	login := do(http.MethodPost, "/login", nil)
+	if login.Code != http.StatusOK {
+		t.Fatalf("Expected login to succeed, got %d", login.Code)
+	}
+	cookies := login.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session_id" || cookies[0].Value == "" || !cookies[0].HttpOnly {
+		t.Fatalf("Expected an HttpOnly session_id cookie, got %v", cookies)
+	}
+	session := cookies[0]
+
+	profile := do(http.MethodGet, "/profile", session)
+	if profile.Code != http.StatusOK {
+		t.Fatalf("Expected authenticated GET to succeed, got %d", profile.Code)
+	}
+	if body := profile.Body.String(); body != `{"name":"Ada"}` {
+		t.Errorf("Unexpected profile body %s", body)
+	}
+
+	// A second login issues a different session even though the response is cached
+	if again := do(http.MethodPost, "/login", nil).Result().Cookies(); len(again) != 1 || again[0].Value == session.Value {
+		t.Errorf("Expected a new session cookie on every login, got %v", again)
+	}
+
+	logout := do(http.MethodPost, "/logout", session)
+	if logout.Code != http.StatusNoContent {
+		t.Fatalf("Expected logout to succeed, got %d", logout.Code)
+	}
+	if cleared := logout.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("Expected logout to clear the cookie, got %v", cleared)
+	}
+	if rec := do(http.MethodGet, "/profile", session); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 after logout, got %d", rec.Code)
+	}
+}
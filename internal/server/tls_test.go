@@ -0,0 +1,50 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateSelfSignedCertificate(t *testing.T) {
+	cert, err := generateSelfSignedCertificate("mock.internal")
+	if err != nil {
+		t.Fatalf("Failed to generate certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse generated certificate: %v", err)
+	}
+	for _, name := range []string{"localhost", "mock.internal"} {
+		if err := leaf.VerifyHostname(name); err != nil {
+			t.Errorf("Expected certificate to be valid for %s: %v", name, err)
+		}
+	}
+	if err := leaf.VerifyHostname("127.0.0.1"); err != nil {
+		t.Errorf("Expected certificate to be valid for 127.0.0.1: %v", err)
+	}
+
+	// The certificate must be usable for serving HTTPS and trusted once pinned
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	ts.StartTLS()
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("HTTPS request with self-signed certificate failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,51 @@
+// Package version holds build information, set at build time with
+//
+//	-ldflags "-X github.com/leslieo2/go-spec-mock/internal/version.Version=v1.2.3
+//	  -X github.com/leslieo2/go-spec-mock/internal/version.Commit=abc1234
+//	  -X github.com/leslieo2/go-spec-mock/internal/version.Date=2025-01-01T00:00:00Z"
+//
+// Binaries built without these flags, such as with go install, fall back to the
+// module version and VCS details recorded by the Go toolchain.
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build information; overridden with -ldflags -X
+var (
+	Version = "dev"
+	Commit  = "unknown"
+	Date    = "unknown"
+)
+
+func init() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if Commit == "unknown" && setting.Value != "" {
+				Commit = setting.Value
+				if len(Commit) > 7 {
+					Commit = Commit[:7]
+				}
+			}
+		case "vcs.time":
+			if Date == "unknown" && setting.Value != "" {
+				Date = setting.Value
+			}
+		}
+	}
+}
+
+// String describes the build on one line, e.g. for --version
+func String() string {
+	return fmt.Sprintf("go-spec-mock %s (commit %s, built %s)", Version, Commit, Date)
+}
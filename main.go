@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/leslieo2/go-spec-mock/internal/constants"
 	"github.com/leslieo2/go-spec-mock/internal/hotreload"
 	"github.com/leslieo2/go-spec-mock/internal/server"
+	"github.com/leslieo2/go-spec-mock/internal/version"
 	"github.com/spf13/pflag"
 )
 
@@ -22,7 +24,10 @@ func main() {
 }
 
 func run() error {
-	// No longer require positional arguments
+	// Subcommands come before the server flags
+	if len(os.Args) > 1 && os.Args[1] == genCommand {
+		return runGen(os.Args[2:], os.Stdout)
+	}
 
 	// Parse CLI flags
 	configFile := pflag.String("config", "", "Path to configuration file (YAML or JSON)")
@@ -34,6 +39,7 @@ func run() error {
 
 	// Hot reload flags
 	hotReload := pflag.Bool("hot-reload", true, "Enable hot reload for specification file")
+	watchDir := pflag.String("watch-dir", "", "Also reload when a spec file in this directory changes")
 
 	// Proxy flags
 	proxyEnabled := pflag.Bool("proxy-enabled", false, "Enable proxy mode for undefined endpoints")
@@ -44,8 +50,33 @@ func run() error {
 	tlsCertFile := pflag.String("tls-cert-file", "", "Path to TLS certificate file")
 	tlsKeyFile := pflag.String("tls-key-file", "", "Path to TLS private key file")
 
+	// Generator flags
+	arrayLength := pflag.Int("array-length", config.DefaultArrayLength, "Number of items generated for arrays without size constraints")
+
+	// Starter configuration
+	initConfig := pflag.Bool("init-config", false, "Print a commented default configuration file and exit")
+	configCheck := pflag.Bool("config-check", false, "Validate the configuration and exit without requiring a spec file")
+
+	// Developer convenience
+	openBrowserFlag := registerOpenBrowserFlag(pflag.CommandLine)
+	showVersion := pflag.Bool("version", false, "Print version information and exit")
+
 	pflag.Parse()
 
+	if *showVersion {
+		fmt.Println(version.String())
+		return nil
+	}
+
+	if *initConfig {
+		data, err := config.MarshalAnnotatedYAML(config.DefaultConfig())
+		if err != nil {
+			return fmt.Errorf("failed to generate starter configuration: %w", err)
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
 	// Create signal-aware context for graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -56,11 +87,21 @@ func run() error {
 		Port:         port,
 		SpecFile:     specFile,
 		HotReload:    hotReload,
+		WatchDir:     watchDir,
 		ProxyEnabled: proxyEnabled,
 		ProxyTarget:  proxyTarget,
 		TLSEnabled:   tlsEnabled,
 		TLSCertFile:  tlsCertFile,
 		TLSKeyFile:   tlsKeyFile,
+		ArrayLength:  arrayLength,
+	}
+
+	if *configCheck {
+		if err := checkConfig(*configFile, cliFlags); err != nil {
+			return err
+		}
+		fmt.Println("Configuration is valid")
+		return nil
 	}
 
 	// Load configuration with precedence (CLI flags > Environment variables > Config file > Defaults)
@@ -75,7 +116,12 @@ func run() error {
 		printUsage()
 		os.Exit(1)
 	}
-	if _, err := os.Stat(cfg.SpecFile); os.IsNotExist(err) {
+	if cfg.SpecFile == constants.SpecFileStdin {
+		if cfg.HotReload.Enabled {
+			log.Printf("Hot reload disabled for a spec read from stdin")
+			cfg.HotReload.Enabled = false
+		}
+	} else if _, err := os.Stat(cfg.SpecFile); os.IsNotExist(err) {
 		return fmt.Errorf("OpenAPI spec file not found: %s", cfg.SpecFile)
 	}
 	if err := cfg.Validate(); err != nil {
@@ -104,6 +150,13 @@ func run() error {
 			return fmt.Errorf("failed to watch spec file: %w", err)
 		}
 
+		// Watch the extra directory, e.g. for files referenced with $ref
+		if cfg.HotReload.WatchDir != "" {
+			if err := hotReloadManager.AddWatchDir(cfg.HotReload.WatchDir); err != nil {
+				return fmt.Errorf("failed to watch directory: %w", err)
+			}
+		}
+
 		// Register the server as a reloadable component
 		if err := hotReloadManager.RegisterReloadable(mockServer); err != nil {
 			return fmt.Errorf("failed to register server for hot reload: %w", err)
@@ -117,6 +170,10 @@ func run() error {
 		log.Printf("Hot reload enabled for %s", cfg.SpecFile)
 	}
 
+	if *openBrowserFlag {
+		go openWhenListening(ctx, browserAddress(cfg), docsURL(cfg), openBrowser)
+	}
+
 	log.Printf("Starting mock server for %s on %s:%s", cfg.SpecFile, cfg.Server.Host, cfg.Server.Port)
 	if err := mockServer.Start(); err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
@@ -137,13 +194,26 @@ func run() error {
 	return nil
 }
 
+// checkConfig loads the configuration, which runs Config.Validate, without
+// requiring a spec file, so CI can lint configuration on its own
+func checkConfig(configFile string, flags *config.CLIFlags) error {
+	if _, err := config.LoadConfig(configFile, flags); err != nil {
+		return fmt.Errorf("configuration check failed: %w", err)
+	}
+	return nil
+}
+
 // printUsage prints the usage information
 func printUsage() {
 	fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\nRequired:\n")
-	fmt.Fprintf(os.Stderr, "  --spec-file\t\tPath to OpenAPI specification file\n")
+	fmt.Fprintf(os.Stderr, "  --spec-file\t\tPath to OpenAPI specification file, or - to read it from stdin\n")
 	fmt.Fprintf(os.Stderr, "\nConfiguration options:\n")
 	fmt.Fprintf(os.Stderr, "  --config\t\tPath to configuration file (YAML or JSON)\n")
+	fmt.Fprintf(os.Stderr, "  --init-config\t\tPrint a commented default configuration file and exit\n")
+	fmt.Fprintf(os.Stderr, "  --config-check\t\tValidate the configuration and exit (no spec file needed)\n")
+	fmt.Fprintf(os.Stderr, "  --open-browser, --open\tOpen the documentation page in the default browser on startup\n")
+	fmt.Fprintf(os.Stderr, "  --version\t\tPrint version information and exit\n")
 	fmt.Fprintf(os.Stderr, "\nServer configuration:\n")
 	fmt.Fprintf(os.Stderr, "  --host\t\t\tHost to run the mock server on (default: localhost)\n")
 	fmt.Fprintf(os.Stderr, "  --port\t\t\tPort to run the mock server on (default: 8080)\n")
@@ -154,18 +224,27 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "  --tls-enabled\t\tEnable HTTPS/TLS (default: false)\n")
 	fmt.Fprintf(os.Stderr, "  --tls-cert-file\t\tPath to TLS certificate file\n")
 	fmt.Fprintf(os.Stderr, "  --tls-key-file\t\tPath to TLS private key file\n")
+	fmt.Fprintf(os.Stderr, "\nGenerator flags:\n")
+	fmt.Fprintf(os.Stderr, "  --array-length\t\tNumber of items generated for arrays without size constraints (default: 2)\n")
 	fmt.Fprintf(os.Stderr, "\nHot reload flags:\n")
 	fmt.Fprintf(os.Stderr, "  --hot-reload\t\tEnable hot reload for specification file (default: true)\n")
+	fmt.Fprintf(os.Stderr, "  --watch-dir\t\tAlso reload when a .yaml, .yml, or .json file in this directory changes\n")
+	fmt.Fprintf(os.Stderr, "\nSubcommands:\n")
+	fmt.Fprintf(os.Stderr, "  gen\t\t\tPrint a generated example for an operation without starting a server\n")
 	fmt.Fprintf(os.Stderr, "\nEnvironment variables:\n")
 	fmt.Fprintf(os.Stderr, "  GO_SPEC_MOCK_HOST, GO_SPEC_MOCK_PORT\n")
 	fmt.Fprintf(os.Stderr, "  GO_SPEC_MOCK_READ_TIMEOUT, GO_SPEC_MOCK_WRITE_TIMEOUT, GO_SPEC_MOCK_IDLE_TIMEOUT\n")
 	fmt.Fprintf(os.Stderr, "  GO_SPEC_MOCK_MAX_REQUEST_SIZE, GO_SPEC_MOCK_SHUTDOWN_TIMEOUT, GO_SPEC_MOCK_SPEC_FILE\n")
 	fmt.Fprintf(os.Stderr, "  GO_SPEC_MOCK_HOT_RELOAD, GO_SPEC_MOCK_HOT_RELOAD_DEBOUNCE\n")
+	fmt.Fprintf(os.Stderr, "  GO_SPEC_MOCK_ARRAY_LENGTH, GO_SPEC_MOCK_ADMIN_TOKEN\n")
 	fmt.Fprintf(os.Stderr, "\nConfiguration file:\n")
 	fmt.Fprintf(os.Stderr, "  go-spec-mock.yaml (default configuration file)\n")
 	fmt.Fprintf(os.Stderr, "\nExample usage:\n")
 	fmt.Fprintf(os.Stderr, "  %s --spec-file ./examples/petstore.yaml --proxy-enabled\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "  %s --spec-file ./examples/petstore.yaml --config ./config.yaml\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "  %s --spec-file ./examples/petstore.yaml --port 8081\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s --config ./config.yaml --config-check\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s gen --spec ./examples/petstore.yaml --operation listPets --status 200 --seed 42\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  cat ./examples/petstore.yaml | %s --spec-file -\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "  GO_SPEC_MOCK_PORT=8081 %s --spec-file ./examples/petstore.yaml\n", os.Args[0])
 }
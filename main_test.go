@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leslieo2/go-spec-mock/internal/config"
+	"github.com/spf13/pflag"
+)
+
+func TestOpenBrowserFlag(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	openBrowser := registerOpenBrowserFlag(fs)
+	if *openBrowser {
+		t.Fatal("expected --open-browser to default to false")
+	}
+
+	if err := fs.Parse([]string{"--open-browser"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	if !*openBrowser {
+		t.Fatal("expected --open-browser to be set")
+	}
+
+	alias := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	openBrowser = registerOpenBrowserFlag(alias)
+	if err := alias.Parse([]string{"--open"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	if !*openBrowser {
+		t.Fatal("expected --open to enable opening the browser")
+	}
+}
+
+func TestHeadless(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+
+	if !headless("linux", env(nil)) {
+		t.Error("expected linux without a display to be headless")
+	}
+	if headless("linux", env(map[string]string{"DISPLAY": ":0"})) {
+		t.Error("expected linux with DISPLAY to have a display")
+	}
+	if headless("linux", env(map[string]string{"WAYLAND_DISPLAY": "wayland-0"})) {
+		t.Error("expected linux with WAYLAND_DISPLAY to have a display")
+	}
+	if headless("darwin", env(nil)) || headless("windows", env(nil)) {
+		t.Error("expected macOS and Windows to always have a display")
+	}
+}
+
+func TestDocsURL(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Server.Host = "0.0.0.0"
+	cfg.Server.Port = "9090"
+	if got := docsURL(cfg); got != "http://localhost:9090/docs" {
+		t.Errorf("unexpected docs URL %q", got)
+	}
+
+	cfg.Server.Host = "127.0.0.1"
+	cfg.TLS.Enabled = true
+	if got := docsURL(cfg); got != "https://127.0.0.1:9090/docs" {
+		t.Errorf("unexpected docs URL %q", got)
+	}
+}
+
+func TestOpenWhenListeningInvokesOpener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	var opened []string
+	opener := func(url string) error {
+		opened = append(opened, url)
+		return errors.New("no browser available")
+	}
+
+	// The opener's error is only logged
+	openWhenListening(context.Background(), listener.Addr().String(), "http://example.test/docs", opener)
+
+	if len(opened) != 1 || opened[0] != "http://example.test/docs" {
+		t.Fatalf("expected opener to be called once with the docs URL, got %v", opened)
+	}
+}
+
+func TestOpenWhenListeningSkipsOpenerWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	openWhenListening(ctx, "127.0.0.1:1", "http://example.test/docs", func(string) error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Fatal("expected opener not to be called when the server never became reachable")
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		return path
+	}
+
+	if err := checkConfig(write("server:\n  port: \"9090\"\n"), nil); err != nil {
+		t.Errorf("expected a valid config without a spec file to pass, got %v", err)
+	}
+
+	err := checkConfig(write("server:\n  compression_level: 12\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "compression_level") {
+		t.Errorf("expected a validation error naming compression_level, got %v", err)
+	}
+
+	if err := checkConfig(write("server: [\n"), nil); err == nil {
+		t.Error("expected an unparsable config to fail")
+	}
+}
+
+func TestRunGen(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "spec.yaml")
+	content := `openapi: 3.0.0
+info:
+  title: Gen
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    id:
+                      type: string
+                      format: uuid
+                    name:
+                      type: string
+                    code:
+                      type: string
+                      pattern: "^[A-Z]{3}-[0-9]{4}$"
+                    bornAt:
+                      type: string
+                      format: date-time
+                    weight:
+                      type: number
+`
+	if err := os.WriteFile(spec, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	gen := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := runGen(append([]string{"--spec", spec}, args...), &out); err != nil {
+			t.Fatalf("gen %v failed: %v", args, err)
+		}
+		return out.String()
+	}
+
+	first := gen("--operation", "listPets", "--status", "200", "--seed", "42")
+	var pets []map[string]interface{}
+	if err := json.Unmarshal([]byte(first), &pets); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", first, err)
+	}
+	if len(pets) == 0 || pets[0]["id"] == nil {
+		t.Fatalf("expected generated pets, got %s", first)
+	}
+	if again := gen("--operation", "listPets", "--seed", "42"); again != first {
+		t.Errorf("expected the same seed to repeat the output\nfirst:  %s\nsecond: %s", first, again)
+	}
+	if byPath := gen("--operation", "get /pets", "--seed", "42"); byPath != first {
+		t.Errorf("expected METHOD /path to select the same operation, got %s", byPath)
+	}
+	if other := gen("--operation", "listPets", "--seed", "7"); other == first {
+		t.Error("expected a different seed to change the output")
+	}
+
+	var out bytes.Buffer
+	if err := runGen([]string{"--spec", spec, "--operation", "missing"}, &out); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an unknown operation to fail, got %v", err)
+	}
+	if err := runGen([]string{"--spec", spec}, &out); err == nil {
+		t.Error("expected gen without --operation to fail")
+	}
+}